@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+)
+
+const (
+	__AFFINITY_TEXT__    = "text"
+	__AFFINITY_NUMACTL__ = "numactl"
+	__AFFINITY_CPUSET__  = "cpuset"
+	__NUMACTL_COMMAND__  = "numactl"
+)
+
+var affinityOutputs = []string{__AFFINITY_TEXT__, __AFFINITY_NUMACTL__, __AFFINITY_CPUSET__}
+
+// queryAffinity returns the affinity of the chips selected by ids, every chip has to be selected explicitly.
+func queryAffinity(ids []string) (exporter.Affinity, error) {
+	if len(ids) == 0 {
+		return exporter.Affinity{}, errors.New("select the chips with --id")
+	}
+	selection, err := exporter.QuerySelection(ids)
+	if err != nil {
+		return exporter.Affinity{}, err
+	}
+	chips, err := exporter.QueryTopology()
+	if err != nil {
+		return exporter.Affinity{}, err
+	}
+	var selected []exporter.ChipTopology
+	for _, c := range chips {
+		if selection.HasChip(c.ChipIndex) {
+			selected = append(selected, c)
+		}
+	}
+	return exporter.ChipAffinity(selected)
+}
+
+// numactlArgs binds the CPUs and, when the chips have a NUMA node, the memory.
+func numactlArgs(a exporter.Affinity) []string {
+	args := []string{"--physcpubind=" + a.CPUList()}
+	if mems := a.MemList(); mems != "" {
+		args = append(args, "--membind="+mems)
+	}
+	return args
+}
+
+func printAffinity(w io.Writer, output string, a exporter.Affinity) {
+	mems := a.MemList()
+	switch output {
+	case __AFFINITY_NUMACTL__:
+		for i, arg := range numactlArgs(a) {
+			if i > 0 {
+				fmt.Fprint(w, " ")
+			}
+			fmt.Fprint(w, arg)
+		}
+		fmt.Fprintln(w)
+	case __AFFINITY_CPUSET__:
+		fmt.Fprintf(w, "cpuset.cpus=%s\n", a.CPUList())
+		if mems != "" {
+			fmt.Fprintf(w, "cpuset.mems=%s\n", mems)
+		}
+	default:
+		if mems == "" {
+			mems = __TOP_NO_VALUE__
+		}
+		fmt.Fprintf(w, "CPU Affinity: %s\nNUMA Affinity: %s\n", a.CPUList(), mems)
+	}
+}
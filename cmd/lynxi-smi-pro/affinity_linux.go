@@ -0,0 +1,38 @@
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"fmt"
+	"golang.org/x/sys/unix"
+	"lynxi_smi_pro/pkg/exporter"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// execPinned replaces the process by command, run through numactl when it is installed, pinned to the CPUs of a
+// otherwise. Memory is only bound by numactl.
+func execPinned(a exporter.Affinity, command []string) error {
+	if numactl, err := exec.LookPath(__NUMACTL_COMMAND__); err == nil {
+		args := append(append([]string{numactl}, numactlArgs(a)...), "--")
+		return syscall.Exec(numactl, append(args, command...), os.Environ())
+	}
+	path, err := exec.LookPath(command[0])
+	if err != nil {
+		return err
+	}
+	// the affinity is set for the calling thread, which is the one that execs.
+	runtime.LockOSThread()
+	var set unix.CPUSet
+	for _, cpu := range a.CPUs {
+		set.Set(cpu)
+	}
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, __NUMACTL_COMMAND__+" not found, memory is not bound to the NUMA nodes of the chips")
+	return syscall.Exec(path, command, os.Environ())
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import (
+	"errors"
+	"lynxi_smi_pro/pkg/exporter"
+)
+
+func execPinned(a exporter.Affinity, command []string) error {
+	return errors.New("running a pinned command is only supported on Linux")
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"lynxi_smi_pro/pkg/cdi"
+	"lynxi_smi_pro/pkg/exporter"
+	"os"
+)
+
+// generateCDISpec writes the CDI spec of the chips to path, to stdout as YAML when path is empty.
+func generateCDISpec(path string, libDirs []string) error {
+	chips, err := exporter.QueryChipDevices()
+	if err != nil {
+		return err
+	}
+	spec := cdi.Generate(chips, cdi.FindLibraries(libDirs))
+	data, err := spec.Marshal(cdi.FormatOf(path))
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"lynxi_smi_pro/pkg/exporter"
+)
+
+// runCheck evaluates the thresholds over the selected boards and chips, errors are reported as an unknown result.
+func runCheck(ids []string, warning []string, critical []string) exporter.CheckResult {
+	thresholds, err := exporter.ParseCheckThresholds(warning, critical)
+	if err != nil {
+		return exporter.CheckUnknownResult(err)
+	}
+	boards, err := exporter.QueryBoards()
+	if err != nil {
+		return exporter.CheckUnknownResult(err)
+	}
+	boards, selection, err := selectBoardBaseInfo(boards, ids)
+	if err != nil {
+		return exporter.CheckUnknownResult(err)
+	}
+	return exporter.RunCheck(boards, thresholds, selection)
+}
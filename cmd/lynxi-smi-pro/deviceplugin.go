@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"lynxi_smi_pro/pkg/deviceplugin"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// runDevicePlugin serves the device plugin until SIGINT or SIGTERM.
+func runDevicePlugin(dir string, resourceName string, healthInterval time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	p := deviceplugin.New(deviceplugin.Options{PluginDir: dir, ResourceName: resourceName, HealthInterval: healthInterval})
+	return p.Run(ctx)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"text/tabwriter"
+)
+
+// printDiag writes one line per check and the overall verdict.
+func printDiag(w io.Writer, results []exporter.DiagResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tTARGET\tVERDICT\tDETAILS")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Check, r.Target, r.Verdict, r.Details)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nResult: %s\n", exporter.WorstVerdict(results))
+	return err
+}
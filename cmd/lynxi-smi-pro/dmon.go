@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	__DMON_DEFAULT_GROUPS__ = "puct"
+	__DMON_NO_VALUE__       = "-"
+	__DMON_COMMENT__        = "#"
+)
+
+// dmonColumn is a column of the dmon output, group is the -s letter that selects it.
+type dmonColumn struct {
+	group byte
+	name  string
+	unit  string
+	width int
+	value func(board exporter.BoardMetrics, chip exporter.ChipMetrics) string
+}
+
+func dmonFloat(v *float64, prec int) string {
+	if v == nil {
+		return __DMON_NO_VALUE__
+	}
+	return strconv.FormatFloat(*v, 'f', prec, 64)
+}
+
+func dmonPercent(v *exporter.Percent) string {
+	if v == nil {
+		return __DMON_NO_VALUE__
+	}
+	return strconv.FormatFloat(float64(*v), 'f', 0, 64)
+}
+
+func dmonMHz(v *exporter.MHz) string {
+	if v == nil {
+		return __DMON_NO_VALUE__
+	}
+	return strconv.FormatFloat(float64(*v), 'f', 0, 64)
+}
+
+func dmonCounter(v *uint64) string {
+	if v == nil {
+		return __DMON_NO_VALUE__
+	}
+	return strconv.FormatUint(*v, 10)
+}
+
+// dmonColumns are the columns of dmon, bpwr is the power draw of the board repeated on the rows of its chips.
+var dmonColumns = []dmonColumn{
+	{'p', "bpwr", "W", 7, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string {
+		if b.PowerDraw == nil {
+			return __DMON_NO_VALUE__
+		}
+		return strconv.FormatFloat(float64(*b.PowerDraw), 'f', 2, 64)
+	}},
+	{'p', "volt", "V", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string {
+		if c.Voltage == nil {
+			return __DMON_NO_VALUE__
+		}
+		return strconv.FormatFloat(float64(*c.Voltage), 'f', 2, 64)
+	}},
+	{'u', "apu", "%", 4, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonPercent(c.Utilization.Apu) }},
+	{'u', "cpu", "%", 4, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonPercent(c.Utilization.Cpu) }},
+	{'u', "vic", "%", 4, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonPercent(c.Utilization.Vic) }},
+	{'u', "mem", "%", 4, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonPercent(c.Utilization.Memory) }},
+	{'u', "ipe", "fps", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonFloat(c.IpeFps, 0) }},
+	{'c', "aclk", "MHz", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonMHz(c.Clocks.Apu) }},
+	{'c', "cclk", "MHz", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonMHz(c.Clocks.Cpu) }},
+	{'c', "mclk", "MHz", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonMHz(c.Clocks.Memory) }},
+	{'t', "temp", "C", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string {
+		if c.Temperature == nil {
+			return __DMON_NO_VALUE__
+		}
+		return strconv.FormatFloat(float64(*c.Temperature), 'f', 0, 64)
+	}},
+	{'e', "cecc", "", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string { return dmonCounter(c.EccErrorsCorrected) }},
+	{'e', "uecc", "", 5, func(b exporter.BoardMetrics, c exporter.ChipMetrics) string {
+		return dmonCounter(c.EccErrorsUncorrected)
+	}},
+}
+
+// selectDmonColumns returns the columns of the groups in sel, in the order of dmonColumns.
+func selectDmonColumns(sel string) ([]dmonColumn, error) {
+	groups := make(map[byte]bool)
+	for i := 0; i < len(sel); i++ {
+		groups[sel[i]] = false
+	}
+	var columns []dmonColumn
+	for _, c := range dmonColumns {
+		if _, ok := groups[c.group]; ok {
+			columns = append(columns, c)
+			groups[c.group] = true
+		}
+	}
+	for g, used := range groups {
+		if !used {
+			return nil, fmt.Errorf("metric group %q is not valid, expected letters of %s", string(g), "pucte")
+		}
+	}
+	return columns, nil
+}
+
+func writeDmonHeader(w io.Writer, columns []dmonColumn) {
+	names := []string{fmt.Sprintf("%s%5s %4s", __DMON_COMMENT__, "board", "chip")}
+	units := []string{fmt.Sprintf("%s%5s %4s", __DMON_COMMENT__, "Idx", "Idx")}
+	for _, c := range columns {
+		names = append(names, fmt.Sprintf("%*s", c.width, c.name))
+		units = append(units, fmt.Sprintf("%*s", c.width, c.unit))
+	}
+	fmt.Fprintln(w, strings.Join(names, " "))
+	fmt.Fprintln(w, strings.Join(units, " "))
+}
+
+func writeDmonRows(w io.Writer, columns []dmonColumn, boards []exporter.BoardMetrics) {
+	for _, b := range boards {
+		for _, chip := range b.Chips {
+			row := []string{fmt.Sprintf(" %5d %4d", b.BoardIndex, chip.ChipIndex)}
+			for _, c := range columns {
+				row = append(row, fmt.Sprintf("%*s", c.width, c.value(b, chip)))
+			}
+			fmt.Fprintln(w, strings.Join(row, " "))
+		}
+	}
+}
+
+func runDmon(w io.Writer, ids []string, sel string, delay time.Duration, count int) error {
+	columns, err := selectDmonColumns(sel)
+	if err != nil {
+		return err
+	}
+	if delay <= 0 {
+		return fmt.Errorf("delay must be greater than 0")
+	}
+	writeDmonHeader(w, columns)
+	return runLoop(delay, count, func() error {
+		boards, err := queryBoardMetrics(ids)
+		if err != nil {
+			return err
+		}
+		writeDmonRows(w, columns, boards)
+		return nil
+	})
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"lynxi_smi_pro/internal/simulator"
+	"lynxi_smi_pro/pkg/cdi"
+	"lynxi_smi_pro/pkg/exporter"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// e2eEnv holds the binaries built for the end-to-end tests and the simulated system they run against.
+type e2eEnv struct {
+	dir   string
+	pro   string
+	sim   string
+	bin   string
+	sysfs string
+}
+
+func buildBinary(t *testing.T, dir string, pkg string) string {
+	t.Helper()
+	out := filepath.Join(dir, filepath.Base(pkg))
+	cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "build", "-o", out, pkg)
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build %s: %v\n%s", pkg, err, b)
+	}
+	return out
+}
+
+func newE2EEnv(t *testing.T) *e2eEnv {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("end-to-end tests build binaries, skipped in short mode")
+	}
+	dir := t.TempDir()
+	env := &e2eEnv{
+		dir:   dir,
+		pro:   buildBinary(t, dir, "lynxi_smi_pro/cmd/lynxi-smi-pro"),
+		sim:   buildBinary(t, dir, "lynxi_smi_pro/cmd/lynxi-smi-sim"),
+		bin:   filepath.Join(dir, "bin"),
+		sysfs: filepath.Join(dir, "bin", "sys"),
+	}
+	return env
+}
+
+// install links the simulator for cfg and returns the environment to run lynxi-smi-pro with.
+func (e *e2eEnv) install(t *testing.T, cfg simulator.Config) []string {
+	t.Helper()
+	_ = os.RemoveAll(e.bin)
+	cmd := exec.Command(e.sim, "install", e.bin)
+	cmd.Env = append(os.Environ(), cfg.Env()...)
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("lynxi-smi-sim install: %v\n%s", err, b)
+	}
+	return append(os.Environ(), append(cfg.Env(), "PATH="+e.bin+string(os.PathListSeparator)+os.Getenv("PATH"))...)
+}
+
+func (e *e2eEnv) run(env []string, args ...string) (string, error) {
+	cmd := exec.Command(e.pro, append([]string{"--sysfs-root=" + e.sysfs}, args...)...)
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func TestE2EFlags(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 2, Chips: 3, Scenario: simulator.ScenarioNormal})
+	cases := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"default", nil, []string{"APU-SMI: V1.6.0", "Driver Version: V1.6.0", "2203A0013"}},
+		{"query", []string{"-q"}, []string{"Board: 0", "Board: 1", "Bus Num:", "NumaCpuList:", "Driver Version             : V1.6.0"}},
+		{"query board", []string{"-q", "-i", "1"}, []string{"Board: 1", "2203A0013"}},
+		{"query chip", []string{"-q", "-i", "1", "-c", "2"}, []string{"Chip2                  : 5"}},
+		{"list apus", []string{"-L"}, []string{"APU 0:HP300  (SN: 2203A0012, ChipCount: 3)", "APU 1:HP300  (SN: 2203A0013, ChipCount: 3)"}},
+		{"chip count", []string{"--chip-count"}, []string{"ChipTotalNumbyPci: 6"}},
+		{"chip list", []string{"--chip-list"}, []string{"03:00.0 Processing accelerators", "08:00.0 Processing accelerators"}},
+		{"query apu", []string{"--query-apu=board_index,serial_number,pci.bus.chip2,temperature.current.chip1"},
+			[]string{"board_index, serial_number, pci.bus.chip2, temperature.current.chip1", "0, 2203A0012, 05, 46", "1, 2203A0013, 08, 46"}},
+		{"query apu csv", []string{"--query-apu=board_index,power.draw", "--format=csv,noheader,nounits"}, []string{"0,31.50\n1,31.50\n"}},
+		{"query apu json", []string{"--query-apu=board_index,power.draw", "--format=json"}, []string{`"power.draw": "31.50 W"`}},
+		{"help query apu", []string{"--help-query-apu"}, []string{"temperature.current.chip2", "temperature.headroom.chip2"}},
+		{"query temperature thresholds", []string{"--query-apu=temperature.current.chip1,temperature.slowdown.chip1,temperature.shutdown.chip1,temperature.headroom.chip1", "--format=csv,noheader"},
+			[]string{"46,95,105,49\n"}},
+		{"dmon", []string{"dmon", "-s", "pt", "--count=1"}, []string{"#board chip    bpwr  volt  temp", "     1    5   31.50  0.80    47"}},
+		{"id query", []string{"-q", "--id=chip4"}, []string{"Board: 1", "Chip1                  : 1e9f27c5-4c59-4e58-0001-000000000004"}},
+		{"id list apus", []string{"-L", "--id=2203A0013"}, []string{"APU 1:HP300  (SN: 2203A0013, ChipCount: 3)\n"}},
+		{"id query apu", []string{"--query-apu=board_index", "--format=csv,noheader", "--id=0000:08:00.0"}, []string{"1\n"}},
+		{"id query apu chip", []string{"--query-apu=board_index,uuid.chip0,uuid.chip1", "--format=csv,noheader", "--id=chip4"},
+			[]string{"1,N/A,1e9f27c5-4c59-4e58-0001-000000000004\n"}},
+		{"id dmon", []string{"dmon", "-s", "t", "--count=1", "--id=chip1,1e9f27c5-4c59-4e58-0001-000000000005"},
+			[]string{"#  Idx  Idx     C\n     0    1    46\n     1    5    47\n"}},
+		{"sysfs source", []string{"--source=sysfs", "--chip-count"}, []string{"ChipTotalNumbyPci: 6"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := e.run(env, c.args...)
+			if err != nil {
+				t.Fatalf("lynxi-smi-pro %v: %v\n%s", c.args, err, out)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("lynxi-smi-pro %v output does not contain %q:\n%s", c.args, want, out)
+				}
+			}
+		})
+	}
+	t.Run("id not found", func(t *testing.T) {
+		out, err := e.run(env, "-L", "--id=chip6")
+		if err == nil || !strings.Contains(out, `no APU matches the id "chip6"`) {
+			t.Errorf("lynxi-smi-pro -L --id=chip6 = %v:\n%s", err, out)
+		}
+	})
+	t.Run("id events", func(t *testing.T) {
+		out, err := e.run(env, "events", "--count=1", "--id=chip4")
+		if err != nil || strings.Count(out, `"type":"chip_appeared"`) != 1 || !strings.Contains(out, `"chip_index":4`) {
+			t.Errorf("lynxi-smi-pro events --id=chip4 = %v:\n%s", err, out)
+		}
+	})
+	t.Run("sysfs source unsupported", func(t *testing.T) {
+		out, err := e.run(env, "--source=sysfs", "--query-apu=board_index")
+		if err == nil || !strings.Contains(out, "--source=sysfs only supports --chip-count") {
+			t.Errorf("lynxi-smi-pro --source=sysfs --query-apu = %v:\n%s", err, out)
+		}
+		if out, err = e.run(env, "--source=sysfs", "topo"); err != nil || !strings.Contains(out, "chip5") {
+			t.Errorf("lynxi-smi-pro --source=sysfs topo = %v:\n%s", err, out)
+		}
+	})
+	t.Run("query without board", func(t *testing.T) {
+		out, err := e.run(env, "-q", "-c", "0")
+		if err == nil || !strings.Contains(out, "board index is requested") {
+			t.Errorf("lynxi-smi-pro -q -c 0 = %v:\n%s", err, out)
+		}
+	})
+}
+
+func TestE2EScenarios(t *testing.T) {
+	e := newE2EEnv(t)
+	t.Run(simulator.ScenarioBanner, func(t *testing.T) {
+		env := e.install(t, simulator.Config{Boards: 1, Chips: 1, Scenario: simulator.ScenarioBanner})
+		out, err := e.run(env, "--query-apu=board_index,serial_number")
+		if err != nil || !strings.Contains(out, "0, 2203A0012") {
+			t.Errorf("banner output not skipped: %v\n%s", err, out)
+		}
+	})
+	t.Run(simulator.ScenarioNA, func(t *testing.T) {
+		env := e.install(t, simulator.Config{Boards: 1, Chips: 2, Scenario: simulator.ScenarioNA})
+		out, err := e.run(env, "--query-apu=temperature.current.chip0,power.draw")
+		if err != nil || !strings.Contains(out, "N/A, N/A") {
+			t.Errorf("N/A values not reported: %v\n%s", err, out)
+		}
+	})
+	t.Run(simulator.ScenarioEcc, func(t *testing.T) {
+		env := e.install(t, simulator.Config{Boards: 1, Chips: 1, Scenario: simulator.ScenarioEcc})
+		out, err := e.run(env, "dmon", "-s", "e", "--count=1")
+		if err != nil || !strings.Contains(out, "     0    0     2     1") {
+			t.Errorf("ECC errors not reported: %v\n%s", err, out)
+		}
+	})
+	t.Run(simulator.ScenarioHot, func(t *testing.T) {
+		env := e.install(t, simulator.Config{Boards: 1, Chips: 1, Scenario: simulator.ScenarioHot})
+		out, err := e.run(env, "--query-apu=clocks_throttle_reasons.active.chip0,clocks_throttle_reasons.thermal.chip0,clocks_throttle_reasons.unknown.chip0", "--format=csv,noheader")
+		if err != nil || out != "Active,Active,Not Active\n" {
+			t.Errorf("thermal throttling not reported: %v\n%s", err, out)
+		}
+		out, err = e.run(env, "events", "--count=1")
+		if err != nil || !strings.Contains(out, `"type":"chip_appeared"`) || !strings.Contains(out, `"type":"temperature_slowdown_reached"`) {
+			t.Errorf("events of the first sample not reported: %v\n%s", err, out)
+		}
+	})
+	t.Run(simulator.ScenarioMissing, func(t *testing.T) {
+		env := e.install(t, simulator.Config{Boards: 1, Chips: 1, Scenario: simulator.ScenarioMissing})
+		out, err := e.run(env, "--chip-count")
+		if err != nil || !strings.Contains(out, "ChipTotalNumbyPci: 0") {
+			t.Errorf("lynxi-smi-pro --chip-count = %v:\n%s", err, out)
+		}
+	})
+}
+
+func TestE2ELoop(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 1, Chips: 3, Scenario: simulator.ScenarioNormal})
+	var out strings.Builder
+	cmd := exec.Command(e.pro, "--sysfs-root="+e.sysfs, "--query-apu=timestamp,board_index", "--format=csv,noheader", "--loop-ms=100")
+	cmd.Env = env
+	cmd.Stdout = &out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("lynxi-smi-pro did not exit cleanly on SIGINT: %v", err)
+	}
+	rows := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(rows) < 2 {
+		t.Fatalf("expected a row per sample, got:\n%s", out.String())
+	}
+	if rows[0] == rows[1] {
+		t.Errorf("samples have the same timestamp: %q", rows[0])
+	}
+}
+
+func TestE2ETopology(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 3, Chips: 2, Scenario: simulator.ScenarioNormal})
+	out, err := e.run(env, "topo")
+	for _, want := range []string{
+		"chip0  X      BRD    SYS    SYS    PHB    PHB    0-15          0",
+		"chip3  SYS    SYS    BRD    X      SYS    SYS    16-31         1",
+		"  PIX  = Connection traversing PCIe switches",
+	} {
+		if err != nil || !strings.Contains(out, want) {
+			t.Errorf("lynxi-smi-pro topo output does not contain %q: %v\n%s", want, err, out)
+		}
+	}
+	out, err = e.run(env, "--source=sysfs", "topo", "--id=chip0,chip1")
+	if err == nil {
+		t.Errorf("--id resolved without lynxi-smi:\n%s", out)
+	}
+	out, err = e.run(env, "--source=sysfs", "topo")
+	if err != nil || !strings.Contains(out, "chip0  X      PIX    SYS") {
+		t.Errorf("lynxi-smi-pro --source=sysfs topo = %v:\n%s", err, out)
+	}
+}
+
+func TestE2EAffinity(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 2, Chips: 2, Scenario: simulator.ScenarioNormal})
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"affinity", "--id=0"}, "CPU Affinity: 0-15\nNUMA Affinity: 0\n"},
+		{[]string{"affinity", "--id=chip1,chip2", "-o", "numactl"}, "--physcpubind=0-31 --membind=0-1\n"},
+		{[]string{"affinity", "--id=1", "-o", "cpuset"}, "cpuset.cpus=16-31\ncpuset.mems=1\n"},
+		{[]string{"affinity", "--id=0", "--", "sh", "-c", "echo pinned"}, "pinned\n"},
+	}
+	for _, c := range cases {
+		out, err := e.run(env, c.args...)
+		if err != nil || !strings.HasSuffix(out, c.want) {
+			t.Errorf("lynxi-smi-pro %v = %v:\n%s\nwant:\n%s", c.args, err, out, c.want)
+		}
+	}
+	if out, err := e.run(env, "affinity"); err == nil {
+		t.Errorf("lynxi-smi-pro affinity without --id did not fail:\n%s", out)
+	}
+}
+
+func TestE2ELabels(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 2, Chips: 2, Scenario: simulator.ScenarioNormal})
+	want := "lynxi.com/apu.chips-per-board=2\nlynxi.com/apu.count=4\nlynxi.com/apu.driver-version=1.6.0\n" +
+		"lynxi.com/apu.firmware-version=2.1.0\nlynxi.com/apu.pcie-gen=3\nlynxi.com/apu.product=HP200\n"
+	file := filepath.Join(t.TempDir(), "lynxi-apu")
+	out, err := e.run(env, "labels", "--output-file="+file)
+	if err != nil {
+		t.Fatalf("lynxi-smi-pro labels = %v:\n%s", err, out)
+	}
+	if data, err := ioutil.ReadFile(file); err != nil || string(data) != want {
+		t.Errorf("labels file = %v:\n%s\nwant:\n%s", err, data, want)
+	}
+	out, err = e.run(env, "labels", "-o", "json")
+	if err != nil || !strings.Contains(out, `"lynxi.com/apu.count": "4"`) {
+		t.Errorf("lynxi-smi-pro labels -o json = %v:\n%s", err, out)
+	}
+}
+
+func TestE2ECDI(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 1, Chips: 2, Scenario: simulator.ScenarioNormal})
+	file := filepath.Join(t.TempDir(), "lynxi.json")
+	if out, err := e.run(env, "cdi", "generate", "--output-file="+file); err != nil {
+		t.Fatalf("lynxi-smi-pro cdi generate = %v:\n%s", err, out)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec cdi.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("spec is not JSON: %v\n%s", err, data)
+	}
+	var names []string
+	for _, d := range spec.Devices {
+		names = append(names, d.Name)
+	}
+	want := "0,1e9f27c5-4c59-4e58-0000-000000000000,1,1e9f27c5-4c59-4e58-0000-000000000001,all"
+	if spec.Kind != cdi.Kind || strings.Join(names, ",") != want {
+		t.Errorf("spec kind %s, devices %v, want %s", spec.Kind, names, want)
+	}
+	out, err := e.run(env, "cdi", "generate")
+	if err != nil || !strings.Contains(out, "- path: /dev/lynd1\n") {
+		t.Errorf("lynxi-smi-pro cdi generate = %v:\n%s", err, out)
+	}
+}
+
+func TestE2EHook(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 1, Chips: 2, Scenario: simulator.ScenarioNormal})
+	bundle := t.TempDir()
+	runc := filepath.Join(t.TempDir(), "runc")
+	if err := ioutil.WriteFile(runc, []byte("#!/bin/sh\necho runc \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"process":{"env":["LYNXI_VISIBLE_DEVICES=%s"]},"linux":{}}`
+	cases := []struct {
+		visible string
+		want    string
+	}{
+		{"void", "runc --root /run/runc create --bundle " + bundle + " infer\n"},
+		{"1e9f27c5-4c59-4e58-0000-000000000009", `unknown device "1e9f27c5-4c59-4e58-0000-000000000009"`},
+	}
+	for _, c := range cases {
+		if err := ioutil.WriteFile(filepath.Join(bundle, "config.json"), []byte(fmt.Sprintf(config, c.visible)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		out, err := e.run(env, "runtime", "--runtime="+runc, "--", "--root", "/run/runc", "create", "--bundle", bundle, "infer")
+		if (err != nil) != (c.visible != "void") || !strings.Contains(out, c.want) {
+			t.Errorf("lynxi-smi-pro runtime with %s = %v:\n%s", c.visible, err, out)
+		}
+		out, err = e.run(env, "hook", "--bundle="+bundle)
+		if (err != nil) != (c.visible != "void") {
+			t.Errorf("lynxi-smi-pro hook with %s = %v:\n%s", c.visible, err, out)
+		}
+		hook := exec.Command(e.pro, "--sysfs-root="+e.sysfs, "hook")
+		hook.Env = env
+		hook.Stdin = strings.NewReader(fmt.Sprintf(`{"ociVersion":"1.0.2","id":"infer","status":"created","pid":%d,"bundle":%q}`, os.Getpid(), bundle))
+		b, err := hook.CombinedOutput()
+		if (err != nil) != (c.visible != "void") || (err != nil && !strings.Contains(string(b), c.want)) {
+			t.Errorf("lynxi-smi-pro hook with the state of %s on stdin = %v:\n%s", c.visible, err, b)
+		}
+	}
+}
+
+func TestE2EInflux(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 1, Chips: 2, Scenario: simulator.ScenarioNormal})
+	out, err := e.run(env, "--format=influx")
+	if err != nil || strings.Count(out, "\n") != 3 || !strings.Contains(out, "lynxi_apu,board_index=0,chip_index=1,") {
+		t.Errorf("lynxi-smi-pro --format=influx = %v:\n%s", err, out)
+	}
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	out, err = e.run(env, "--influx-url="+server.URL+"/api/v2/write?bucket=apu", "--id=chip1")
+	if err != nil || len(bodies) != 1 || strings.Count(bodies[0], "\n") != 2 || strings.Contains(bodies[0], "chip_index=0") ||
+		!strings.Contains(bodies[0], "lynxi_apu,board_index=0,chip_index=1,") || !strings.Contains(bodies[0], "pcie_link_width_current=4i") {
+		t.Errorf("lynxi-smi-pro --influx-url = %v:\n%s\nposted %q", err, out, bodies)
+	}
+}
+
+func TestE2EDiagAndCheck(t *testing.T) {
+	e := newE2EEnv(t)
+	cases := []struct {
+		scenario string
+		chips    int
+		args     []string
+		code     int
+		want     string
+	}{
+		{simulator.ScenarioNormal, 1, []string{"diag"}, 0, "chip count  system  PASS     1 chips"},
+		{simulator.ScenarioNormal, 2, []string{"diag", "-r", "medium"}, 1, "pcie link    chip1   WARN     degraded, 8 GT/s x4, max 8 GT/s x8"},
+		{simulator.ScenarioHot, 1, []string{"diag", "-r", "medium"}, 2, "temperature  chip0   FAIL     97 C, slowdown at 95 C"},
+		{simulator.ScenarioEcc, 1, []string{"diag", "-r", "medium"}, 2, "ecc          chip0   FAIL     2 corrected, 1 uncorrected"},
+		{simulator.ScenarioNormal, 1, []string{"diag", "-r", "long", "--duration=100ms"}, 0, "ecc stability  chip0   PASS"},
+		{simulator.ScenarioNormal, 1, []string{"check", "--warning", "temperature.current.chip*=80"}, 0,
+			"APU OK - 1 values within thresholds | board0.temperature.current.chip0=45;80\n"},
+		{simulator.ScenarioHot, 1, []string{"check", "--warning", "temperature.current.chip*=80", "--critical", "temperature.current.chip*=95"}, 2,
+			"APU CRITICAL - board0.temperature.current.chip0=97 (critical 95) |"},
+		{simulator.ScenarioEcc, 1, []string{"check", "--warning", "ecc.errors.corrected.total=0"}, 1, "APU WARNING - board0.ecc.errors.corrected.total=2 (warning 0)"},
+		{simulator.ScenarioNormal, 2, []string{"check", "--id", "chip1", "--critical", "temperature.current.chip*=46:"}, 0,
+			"APU OK - 1 values within thresholds | board0.temperature.current.chip1=46;;46:\n"},
+		{simulator.ScenarioMissing, 1, []string{"check", "--critical", "power.draw=75"}, 3, "APU UNKNOWN - no APU found\n"},
+		{simulator.ScenarioNormal, 1, []string{"check", "--warning", "foo"}, 3, `APU UNKNOWN - invalid threshold "foo", expected FIELD=RANGE`},
+		{simulator.ScenarioNormal, 1, []string{"check", "--warning"}, 3, "expected argument for flag '--warning'"},
+	}
+	for _, c := range cases {
+		env := e.install(t, simulator.Config{Boards: 1, Chips: c.chips, Scenario: c.scenario})
+		out, err := e.run(env, c.args...)
+		code := 0
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if code != c.code || !strings.Contains(out, c.want) {
+			t.Errorf("lynxi-smi-pro %v in %s = exit %d:\n%s\nwant exit %d and %q", c.args, c.scenario, code, out, c.code, c.want)
+		}
+	}
+}
+
+func TestE2EReplayCapture(t *testing.T) {
+	e := newE2EEnv(t)
+	capture := filepath.Join(e.dir, "capture")
+	cmd := exec.Command(e.sim, "--boards=2", "--chips=4", "capture", capture)
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("lynxi-smi-sim capture: %v\n%s", err, b)
+	}
+	out, err := e.run(os.Environ(), "--source=replay", "--replay-dir="+capture, "-L")
+	if err != nil || !strings.Contains(out, "APU 1:HP400  (SN: 2203A0013, ChipCount: 4)") {
+		t.Errorf("replay of capture failed: %v\n%s", err, out)
+	}
+}
+
+func TestE2EProcesses(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 1, Chips: 3, Scenario: simulator.ScenarioNormal})
+	procRoot := filepath.Join(e.dir, "proc")
+	fdDir := filepath.Join(procRoot, "4242", "fd")
+	if err := os.MkdirAll(fdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(exporter.DevicePath(2), filepath.Join(fdDir, "3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(procRoot, "4242", "comm"), []byte("infer\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := e.run(env, "--proc-root="+procRoot, "--query-compute-apps=pid,process_name,chip_index,uuid,pci.bus_id", "--format=csv,noheader")
+	if err != nil || !strings.Contains(out, "4242,infer,2,1e9f27c5-4c59-4e58-0000-000000000002,0000:05:00.0") {
+		t.Errorf("lynxi-smi-pro --query-compute-apps = %v:\n%s", err, out)
+	}
+	out, err = e.run(env, "--proc-root="+procRoot, "pmon", "--count=1")
+	if err != nil || !strings.Contains(out, "    2     4242 N/A          infer            -") {
+		t.Errorf("lynxi-smi-pro pmon = %v:\n%s", err, out)
+	}
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestE2EServe(t *testing.T) {
+	e := newE2EEnv(t)
+	env := e.install(t, simulator.Config{Boards: 1, Chips: 3, Scenario: simulator.ScenarioNormal})
+	address := freeAddress(t)
+	cmd := exec.Command(e.pro, "--sysfs-root="+e.sysfs, "serve", "--web.listen-address="+address)
+	cmd.Env = env
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	var body []byte
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get("http://" + address + "/metrics")
+		if err == nil {
+			body, err = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err == nil {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	for _, want := range []string{"lynxi_apu_up 1", `lynxi_apu_temperature_celsius{board_index="0"`, `pci_bus_id="0000:05:00.0"`,
+		`lynxi_apu_temperature_headroom_celsius{board_index="0",chip_index="2",uuid="1e9f27c5-4c59-4e58-0000-000000000002",serial_number="2203A0012"} 48`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
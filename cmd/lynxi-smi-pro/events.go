@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"time"
+)
+
+// runEvents samples the selected boards and chips every delay and writes the events between two samples as NDJSON, until
+// SIGINT or count samples are taken. The ids are resolved once, a selected chip that is no longer reported disappears.
+func runEvents(w io.Writer, ids []string, delay time.Duration, count int) error {
+	if delay <= 0 {
+		return fmt.Errorf("delay must be greater than 0")
+	}
+	selection, err := exporter.QuerySelection(ids)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	var prev []exporter.BoardMetrics
+	return runLoop(delay, count, func() error {
+		now := time.Now()
+		boards, err := exporter.QueryBoardMetrics()
+		if err != nil {
+			return enc.Encode(exporter.QueryFailedEvent(now, err))
+		}
+		boards = selection.FilterBoards(boards)
+		for _, e := range exporter.DiffEvents(prev, boards, now) {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		prev = boards
+		return nil
+	})
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	__FORMAT_CSV__      = "csv"
+	__FORMAT_JSON__     = "json"
+	__FORMAT_NDJSON__   = "ndjson"
+	__FORMAT_YAML__     = "yaml"
+	__FORMAT_TABLE__    = "table"
+	__FORMAT_INFLUX__   = "influx"
+	__FORMAT_NOHEADER__ = "noheader"
+	__FORMAT_NOUNITS__  = "nounits"
+)
+
+var outputFormats = []string{__FORMAT_CSV__, __FORMAT_JSON__, __FORMAT_NDJSON__, __FORMAT_YAML__, __FORMAT_TABLE__, __FORMAT_INFLUX__}
+
+// outputFormat is the parsed value of --format, e.g. csv,noheader,nounits. legacy is set without format name, the values
+// are then separated by ", " without quoting like the output of lynxi-smi.
+type outputFormat struct {
+	name     string
+	noheader bool
+	nounits  bool
+	legacy   bool
+}
+
+func parseOutputFormat(raw string) (outputFormat, error) {
+	format := outputFormat{name: __FORMAT_CSV__}
+	var names []string
+	for _, v := range strings.Split(raw, __COMMA_SEP__) {
+		v = strings.TrimSpace(v)
+		switch v {
+		case "":
+		case __FORMAT_NOHEADER__:
+			format.noheader = true
+		case __FORMAT_NOUNITS__:
+			format.nounits = true
+		case __FORMAT_CSV__, __FORMAT_JSON__, __FORMAT_NDJSON__, __FORMAT_YAML__, __FORMAT_TABLE__, __FORMAT_INFLUX__:
+			names = append(names, v)
+		default:
+			return format, fmt.Errorf("format %q is not valid, expected one of %s with optional %s, %s", v,
+				strings.Join(outputFormats, "|"), __FORMAT_NOHEADER__, __FORMAT_NOUNITS__)
+		}
+	}
+	if len(names) > 1 {
+		return format, fmt.Errorf("only one of %s can be used in a format", strings.Join(names, ", "))
+	}
+	if len(names) == 1 {
+		format.name = names[0]
+	} else {
+		format.legacy = true
+	}
+	return format, nil
+}
+
+// checkLoopFormat rejects json when sampling in a loop, the arrays of the samples would not form a JSON document.
+func checkLoopFormat(format outputFormat, interval time.Duration) error {
+	if format.name == __FORMAT_JSON__ && interval > 0 {
+		return fmt.Errorf("format %s cannot be used with --loop or --loop-ms, use %s to write an object per line", __FORMAT_JSON__, __FORMAT_NDJSON__)
+	}
+	return nil
+}
+
+// records is a table of string values, units holds the unit of each column, e.g. [%].
+type records struct {
+	fields []string
+	units  []string
+	rows   [][]string
+}
+
+func (f outputFormat) title(r records, i int) string {
+	if f.nounits || i >= len(r.units) || r.units[i] == "" {
+		return r.fields[i]
+	}
+	return r.fields[i] + " " + r.units[i]
+}
+
+func (f outputFormat) value(val string) string {
+	if !f.nounits {
+		return val
+	}
+	return stripUnit(val)
+}
+
+// stripUnit removes the unit following a number, e.g. 31.50 W.
+func stripUnit(val string) string {
+	fields := strings.Fields(val)
+	if len(fields) != 2 {
+		return val
+	}
+	if _, err := strconv.ParseFloat(fields[0], 64); err != nil {
+		return val
+	}
+	return fields[0]
+}
+
+func (f outputFormat) write(w io.Writer, r records) error {
+	switch f.name {
+	case __FORMAT_JSON__:
+		return f.writeJSON(w, r)
+	case __FORMAT_NDJSON__:
+		return f.writeNDJSON(w, r)
+	case __FORMAT_YAML__:
+		return f.writeYAML(w, r)
+	case __FORMAT_TABLE__:
+		return f.writeTable(w, r)
+	}
+	if f.legacy {
+		return f.writeLegacy(w, r)
+	}
+	return f.writeCSV(w, r)
+}
+
+// writeCSV writes RFC 4180 records, values containing commas, quotes or line breaks are quoted.
+func (f outputFormat) writeCSV(w io.Writer, r records) error {
+	cw := csv.NewWriter(w)
+	line := make([]string, len(r.fields))
+	if !f.noheader {
+		for i := range r.fields {
+			line[i] = f.title(r, i)
+		}
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	for _, row := range r.rows {
+		for i, val := range row {
+			line[i] = f.value(val)
+		}
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeLegacy writes the values separated by ", " like lynxi-smi, without quoting.
+func (f outputFormat) writeLegacy(w io.Writer, r records) error {
+	line := make([]string, len(r.fields))
+	if !f.noheader {
+		for i := range r.fields {
+			line[i] = f.title(r, i)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(line, __FIELD_SEP__)); err != nil {
+			return err
+		}
+	}
+	for _, row := range r.rows {
+		for i, val := range row {
+			line[i] = f.value(val)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(line, __FIELD_SEP__)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f outputFormat) writeTable(w io.Writer, r records) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	line := make([]string, len(r.fields))
+	if !f.noheader {
+		for i := range r.fields {
+			line[i] = f.title(r, i)
+		}
+		fmt.Fprintln(tw, strings.Join(line, "\t"))
+	}
+	for _, row := range r.rows {
+		for i, val := range row {
+			line[i] = f.value(val)
+		}
+		fmt.Fprintln(tw, strings.Join(line, "\t"))
+	}
+	return tw.Flush()
+}
+
+// jsonObject encodes a row as a JSON object keeping the order of the fields.
+func (f outputFormat) jsonObject(r records, row []string) ([]byte, error) {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, val := range row {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		key, err := json.Marshal(r.fields[i])
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value(val))
+		if err != nil {
+			return nil, err
+		}
+		sb.Write(key)
+		sb.WriteString(":")
+		sb.Write(value)
+	}
+	sb.WriteString("}")
+	return []byte(sb.String()), nil
+}
+
+func (f outputFormat) writeJSON(w io.Writer, r records) error {
+	objects := make([]json.RawMessage, len(r.rows))
+	for i, row := range r.rows {
+		object, err := f.jsonObject(r, row)
+		if err != nil {
+			return err
+		}
+		objects[i] = object
+	}
+	data, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func (f outputFormat) writeNDJSON(w io.Writer, r records) error {
+	for _, row := range r.rows {
+		object, err := f.jsonObject(r, row)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, string(object)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAML writes a sequence with a mapping per row, the keys in the order of the fields.
+func (f outputFormat) writeYAML(w io.Writer, r records) error {
+	rows := make([]yaml.MapSlice, len(r.rows))
+	for i, row := range r.rows {
+		rows[i] = make(yaml.MapSlice, len(row))
+		for j, val := range row {
+			rows[i][j] = yaml.MapItem{Key: r.fields[j], Value: f.value(val)}
+		}
+	}
+	data, err := yaml.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want outputFormat
+		err  bool
+	}{
+		{"", outputFormat{name: __FORMAT_CSV__, legacy: true}, false},
+		{"noheader", outputFormat{name: __FORMAT_CSV__, noheader: true, legacy: true}, false},
+		{"csv,noheader,nounits", outputFormat{name: __FORMAT_CSV__, noheader: true, nounits: true}, false},
+		{"nounits,json", outputFormat{name: __FORMAT_JSON__, nounits: true}, false},
+		{"table", outputFormat{name: __FORMAT_TABLE__}, false},
+		{"csv,json", outputFormat{}, true},
+		{"xml", outputFormat{}, true},
+	}
+	for _, c := range cases {
+		got, err := parseOutputFormat(c.raw)
+		if (err != nil) != c.err {
+			t.Errorf("parseOutputFormat(%q) error = %v, want error %v", c.raw, err, c.err)
+			continue
+		}
+		if !c.err && got != c.want {
+			t.Errorf("parseOutputFormat(%q) = %+v, want %+v", c.raw, got, c.want)
+		}
+	}
+}
+
+func TestWriteRecords(t *testing.T) {
+	r := records{
+		fields: []string{"name", "power.draw"},
+		units:  []string{"", "[W]"},
+		rows:   [][]string{{`HP300, "rev 2"`, "31.50 W"}},
+	}
+	cases := []struct {
+		format string
+		want   string
+	}{
+		{"", "name, power.draw [W]\nHP300, \"rev 2\", 31.50 W\n"},
+		{"csv", "name,power.draw [W]\n\"HP300, \"\"rev 2\"\"\",31.50 W\n"},
+		{"csv,noheader,nounits", "\"HP300, \"\"rev 2\"\"\",31.50\n"},
+		{"ndjson", `{"name":"HP300, \"rev 2\"","power.draw":"31.50 W"}` + "\n"},
+		{"yaml,nounits", "- name: HP300, \"rev 2\"\n  power.draw: \"31.50\"\n"},
+	}
+	for _, c := range cases {
+		format, err := parseOutputFormat(c.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sb strings.Builder
+		if err := format.write(&sb, r); err != nil {
+			t.Fatal(err)
+		}
+		if sb.String() != c.want {
+			t.Errorf("format %s:\ngot:\n%s\nwant:\n%s", c.format, sb.String(), c.want)
+		}
+	}
+}
+
+func TestWriteCSVReadBack(t *testing.T) {
+	r := records{
+		fields: []string{"name", "serial_number", "power.draw"},
+		units:  []string{"", "", "[W]"},
+		rows:   [][]string{{`HP300, "rev 2"`, " 2203A0012", "31.50 W"}, {"HP300\nrev 3", "2203A0013", "N/A"}},
+	}
+	format, err := parseOutputFormat("csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := format.write(&sb, r); err != nil {
+		t.Fatal(err)
+	}
+	got, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("csv output cannot be read back: %v\n%s", err, sb.String())
+	}
+	want := append([][]string{{"name", "serial_number", "power.draw [W]"}}, r.rows...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("csv output read back as %q, want %q", got, want)
+	}
+}
+
+func TestCheckLoopFormat(t *testing.T) {
+	cases := []struct {
+		format   string
+		interval time.Duration
+		err      bool
+	}{
+		{"json", 0, false},
+		{"json", time.Second, true},
+		{"ndjson", time.Second, false},
+		{"csv,noheader", time.Second, false},
+	}
+	for _, c := range cases {
+		format, err := parseOutputFormat(c.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := checkLoopFormat(format, c.interval); (err != nil) != c.err {
+			t.Errorf("checkLoopFormat(%s, %v) = %v, want error %v", c.format, c.interval, err, c.err)
+		}
+	}
+}
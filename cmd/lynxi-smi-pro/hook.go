@@ -0,0 +1,24 @@
+package main
+
+import (
+	"io"
+	"lynxi_smi_pro/pkg/ocihook"
+)
+
+// runHook injects the chips into the config.json of bundle, into the created container of the state on stdin as a
+// prestart hook when bundle is empty.
+func runHook(stdin io.Reader, bundle string) error {
+	if bundle != "" {
+		return ocihook.Inject(bundle, ocihook.Options{})
+	}
+	state, err := ocihook.ReadState(stdin)
+	if err != nil {
+		return err
+	}
+	return ocihook.Prestart(state, ocihook.Options{})
+}
+
+// runRuntime injects the chips into the bundle of the container created by args and runs the runtime with args.
+func runRuntime(runtime string, args []string) error {
+	return ocihook.RunRuntime(runtime, args, ocihook.Options{})
+}
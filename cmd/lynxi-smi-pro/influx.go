@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"time"
+)
+
+// isInfluxFormat reports whether --format selects the InfluxDB line protocol.
+func isInfluxFormat(raw string) bool {
+	outFormat, err := parseOutputFormat(raw)
+	return err == nil && outFormat.name == __FORMAT_INFLUX__
+}
+
+// runInflux samples the boards and chips selected by ids every interval and writes them as InfluxDB line protocol, to w or in
+// batches to the write endpoint url. The buffered samples are posted when the loop ends.
+func runInflux(w io.Writer, ids []string, url string, token string, batch int, interval time.Duration) error {
+	var iw *exporter.InfluxWriter
+	if url != "" {
+		iw = exporter.NewInfluxWriter(url, token, batch)
+	}
+	sample := func() error {
+		metrics, err := queryBoardMetrics(ids)
+		if err != nil {
+			return err
+		}
+		if iw == nil {
+			return exporter.WriteInflux(w, metrics)
+		}
+		return iw.Add(metrics)
+	}
+	err := runLoop(interval, 0, sample)
+	if iw != nil {
+		if ferr := iw.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	__LABELS_FEATURES__ = "features"
+	__LABELS_JSON__     = "json"
+)
+
+var labelsOutputs = []string{__LABELS_FEATURES__, __LABELS_JSON__}
+
+// printLabels prints the labels sorted by name, as name=value lines of a node-feature-discovery features.d file or as a
+// JSON object.
+func printLabels(w io.Writer, output string, labels map[string]string) error {
+	if output == __LABELS_JSON__ {
+		data, err := json.MarshalIndent(labels, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", name, labels[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeLabelsFile(path string, output string, labels map[string]string) error {
+	var buf bytes.Buffer
+	if err := printLabels(&buf, output, labels); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// writeFileAtomic replaces the file through a rename, readers like node-feature-discovery never see a partly written
+// file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
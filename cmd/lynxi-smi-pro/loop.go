@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const __LOOP_TIME_FORMAT__ = "Mon Jan _2 15:04:05.000 2006"
+
+// loopInterval returns the --loop-ms or --loop interval, 0 when sampling once.
+func loopInterval() time.Duration {
+	if *loop_ms > 0 {
+		return time.Duration(*loop_ms) * time.Millisecond
+	}
+	if *loop > 0 {
+		return time.Duration(*loop) * time.Second
+	}
+	return 0
+}
+
+// runLoop calls sample every interval until SIGINT or SIGTERM or count samples are taken, only once when interval is 0.
+// Errors of a single sample are reported without stopping the loop.
+func runLoop(interval time.Duration, count int, sample func() error) error {
+	if interval <= 0 {
+		return sample()
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for i := 1; ; i++ {
+		if err := sample(); err != nil && ctx.Err() == nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		if count > 0 && i >= count {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
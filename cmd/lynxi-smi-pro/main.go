@@ -1,10 +1,17 @@
 package main
 
 import (
+	"fmt"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/alecthomas/kingpin.v2"
-	"lynxi_smi_pro/internal/exporter"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"lynxi_smi_pro/pkg/cdi"
+	"lynxi_smi_pro/pkg/deviceplugin"
+	"lynxi_smi_pro/pkg/exporter"
+	"lynxi_smi_pro/pkg/ocihook"
+	"os"
 	"strconv"
+	"time"
 )
 
 var (
@@ -12,12 +19,67 @@ var (
 	board_id = kingpin.Flag("index", "Target a specific Board index. This Flag is used to query APU or hardware Detail details").Short('i').String()
 	chip_id  = kingpin.Flag("chip_id", "Target a specific Chip ID. This Flag is used to query APU or hardware Detail details").Short('c').String()
 	//type_info      = kingpin.Flag("type", "Show information for type: board,memory, usages,temp, power, volt, ecc-enable, health, product, ecc.").Short('t').PlaceHolder("board").String()
+	device_ids     = kingpin.Flag("id", "Target boards and chips by board index, chip index prefixed with chip, chip UUID, board serial number or PCI address.").PlaceHolder("0,chip4,SERIAL,UUID,0000:03:00.0").String()
 	query_apu      = kingpin.Flag("query-apu", "Query Information about APU.").PlaceHolder("name,driver_version,power,...").String()
+	query_apps     = kingpin.Flag("query-compute-apps", "Query the processes using the chips.").PlaceHolder("pid,process_name,chip_index,...").String()
+	format         = kingpin.Flag("format", "Output format of --query-apu and --query-compute-apps: csv, json, ndjson, yaml or table, optionally followed by noheader and nounits. Without format the values are separated by a comma and a space like lynxi-smi. influx writes the metrics of the boards and chips as InfluxDB line protocol.").PlaceHolder("csv,noheader,nounits").String()
+	influx_url     = kingpin.Flag("influx-url", "Post the metrics of the boards and chips to the InfluxDB write endpoint instead of stdout, in batches of --influx-batch samples.").PlaceHolder("URL").String()
+	influx_token   = kingpin.Flag("influx-token", "API token of --influx-url, also read from INFLUX_TOKEN.").PlaceHolder("TOKEN").Envar("INFLUX_TOKEN").String()
+	influx_batch   = kingpin.Flag("influx-batch", "Number of samples posted together to --influx-url.").Default(strconv.Itoa(exporter.DefaultInfluxBatch)).Int()
 	list_apus      = kingpin.Flag("list-apus", "Display a list of APUs connected to the system.").Short('L').Bool()
 	chip_count     = kingpin.Flag("chip-count", "Displays the number of KA200.").Bool()
 	chip_list      = kingpin.Flag("chip-list", "Displays a list of KA200.").Bool()
 	debug          = kingpin.Flag("debug", "Display Debug Info").Bool()
 	help_query_apu = kingpin.Flag("help-query-apu", "Display Help Query Information about APU.").Bool()
+	loop           = kingpin.Flag("loop", "Repeat the default view, --query-apu or --query-compute-apps every SECONDS until interrupted.").Short('l').PlaceHolder("SECONDS").Int()
+	loop_ms        = kingpin.Flag("loop-ms", "Repeat the default view, --query-apu or --query-compute-apps every MS milliseconds until interrupted.").PlaceHolder("MS").Int()
+	source         = kingpin.Flag("source", "Data source: lynxi-smi, sysfs or replay.").Default(exporter.SourceLynSmi).Enum(exporter.SourceLynSmi, exporter.SourceSysfs, exporter.SourceReplay)
+	sysfs_root     = kingpin.Flag("sysfs-root", "Root of the sysfs tree read for PCI information.").Default(exporter.DefaultSysfsRoot).String()
+	replay_dir     = kingpin.Flag("replay-dir", "Directory with captured outputs used by the replay source.").String()
+	proc_root      = kingpin.Flag("proc-root", "Root of the proc tree scanned for processes using the chips.").Default(exporter.DefaultProcRoot).String()
+
+	info           = kingpin.Command("info", "Display APU information through lynxi-smi (default).").Default()
+	serve          = kingpin.Command("serve", "Expose APU metrics for Prometheus over HTTP.")
+	listen_address = serve.Flag("web.listen-address", "Address on which to expose metrics.").Default(exporter.DefaultListenAddress).String()
+	metrics_path   = serve.Flag("web.telemetry-path", "Path under which to expose metrics.").Default(exporter.DefaultMetricsPath).String()
+	dmon           = kingpin.Command("dmon", "Monitor the chips, one line per chip and interval.")
+	dmon_select    = dmon.Flag("select", "Metric groups to display: p power, u utilization, c clocks, t temperature, e ECC.").Short('s').Default(__DMON_DEFAULT_GROUPS__).String()
+	dmon_delay     = dmon.Flag("delay", "Sampling interval in seconds.").Short('d').Default("1").Int()
+	dmon_count     = dmon.Flag("count", "Number of samples, 0 to run until interrupted.").Default("0").Int()
+	top            = kingpin.Command("top", "Display the boards and chips in a full-screen view refreshed every interval.")
+	top_delay      = top.Flag("delay", "Refresh interval in seconds.").Short('d').Default("1").Int()
+	topo           = kingpin.Command("topo", "Display the connections between the chips and their CPU and NUMA affinity.")
+	affinity       = kingpin.Command("affinity", "Print the CPUs and NUMA nodes local to the chips selected by --id, or run a command pinned to them.")
+	affinity_out   = affinity.Flag("output", "Output: text, numactl arguments or cgroup cpuset.").Short('o').Default(__AFFINITY_TEXT__).Enum(affinityOutputs...)
+	affinity_cmd   = affinity.Arg("command", "Command to run pinned to the CPUs and NUMA nodes, after --.").Strings()
+	pmon           = kingpin.Command("pmon", "Monitor the processes using the chips, one line per process and chip.")
+	pmon_delay     = pmon.Flag("delay", "Sampling interval in seconds.").Short('d').Default("1").Int()
+	pmon_count     = pmon.Flag("count", "Number of samples, 0 to run until interrupted.").Default("0").Int()
+	events         = kingpin.Command("events", "Stream the changes of the boards and chips as NDJSON events.")
+	events_delay   = events.Flag("delay", "Sampling interval in seconds.").Short('d').Default("1").Int()
+	events_count   = events.Flag("count", "Number of samples, 0 to run until interrupted.").Default("0").Int()
+	diag           = kingpin.Command("diag", "Check the health of the driver and the chips, exit with 1 on warnings and 2 on failures.")
+	diag_level     = diag.Flag("level", "Checks to run: quick, medium or long.").Short('r').Default(exporter.DiagQuick).Enum(exporter.DiagLevels...)
+	diag_duration  = diag.Flag("duration", "Sampling time of the long level.").Default(exporter.DefaultDiagDuration.String()).Duration()
+	labels         = kingpin.Command("labels", "Print node-feature-discovery labels of the APU model and driver.")
+	labels_out     = labels.Flag("output", "Output: features, the name=value lines of a features.d file, or json.").Short('o').Default(__LABELS_FEATURES__).Enum(labelsOutputs...)
+	labels_file    = labels.Flag("output-file", "Write the labels to the file instead of stdout, e.g. /etc/kubernetes/node-feature-discovery/features.d/lynxi-apu.").PlaceHolder("FILE").String()
+	cdi_cmd        = kingpin.Command("cdi", "Container Device Interface commands.")
+	cdi_generate   = cdi_cmd.Command("generate", "Generate the CDI spec of the chips, JSON when the output file ends with .json, YAML otherwise.")
+	cdi_output     = cdi_generate.Flag("output-file", "Write the spec to the file instead of stdout, e.g. /etc/cdi/lynxi.yaml.").PlaceHolder("FILE").String()
+	cdi_lib_dirs   = cdi_generate.Flag("library-dir", "Directories searched for the driver libraries, repeat for several directories.").Default(cdi.DefaultLibDirs...).Strings()
+	hook           = kingpin.Command("hook", "OCI prestart hook adding the chips of LYNXI_VISIBLE_DEVICES to the container.")
+	hook_bundle    = hook.Flag("bundle", "Add the chips to the config.json of the bundle before the container is created, instead of the container of the state on stdin.").PlaceHolder("DIR").String()
+	runtime_cmd    = kingpin.Command("runtime", "OCI runtime wrapper adding the chips of LYNXI_VISIBLE_DEVICES to the bundle before create and run.")
+	runtime_path   = runtime_cmd.Flag("runtime", "OCI runtime run with the arguments.").Default(ocihook.DefaultRuntime).String()
+	runtime_args   = runtime_cmd.Arg("args", "Arguments of the runtime, after --.").Strings()
+	plugin         = kingpin.Command("device-plugin", "Register the chips with kubelet as a Kubernetes device plugin.")
+	plugin_dir     = plugin.Flag("plugin-dir", "Directory of the kubelet registration socket and of the plugin socket.").Default(pluginapi.DevicePluginPath).String()
+	plugin_name    = plugin.Flag("resource-name", "Extended resource advertised to kubelet.").Default(deviceplugin.DefaultResourceName).String()
+	plugin_health  = plugin.Flag("health-interval", "Interval between two health checks of the chips.").Default(deviceplugin.DefaultHealthInterval.String()).Duration()
+	check          = kingpin.Command("check", "Check --query-apu fields against thresholds like a Nagios plugin, exit with 0 OK, 1 WARNING, 2 CRITICAL or 3 UNKNOWN.")
+	check_warning  = check.Flag("warning", "Warning range of the fields matching the pattern, e.g. temperature.current.chip*=80.").PlaceHolder("FIELD=RANGE").Strings()
+	check_critical = check.Flag("critical", "Critical range of the fields matching the pattern, e.g. ecc.errors.uncorrected.total=0.").PlaceHolder("FIELD=RANGE").Strings()
 )
 
 func main() {
@@ -27,60 +89,211 @@ func main() {
 	})
 	kingpin.HelpFlag.Short('h')
 	kingpin.UsageTemplate(kingpin.SeparateOptionalFlagsUsageTemplate)
-	kingpin.Parse()
+	kingpin.CommandLine.Terminate(terminate)
+	command := kingpin.Parse()
 	if *debug {
 		log.SetLevel(log.DebugLevel)
 	}
+	if *source == exporter.SourceSysfs && !sysfsSupports(command) {
+		kingpin.Fatalf("--source=%s only supports --chip-count, --chip-list, topo, affinity, cdi generate, hook and runtime without --id, the other commands need the lynxi-smi report", exporter.SourceSysfs)
+	}
+	dataSource, err := exporter.NewSource(*source, *sysfs_root, *replay_dir)
+	kingpin.FatalIfError(err, "")
+	exporter.SetSource(dataSource)
+
+	switch command {
+	case serve.FullCommand():
+		if err := exporter.ServeMetrics(*listen_address, *metrics_path); err != nil {
+			kingpin.Fatalf("%s", err)
+		}
+	case dmon.FullCommand():
+		kingpin.FatalIfError(runDmon(os.Stdout, deviceIds(), *dmon_select, time.Duration(*dmon_delay)*time.Second, *dmon_count), "")
+	case top.FullCommand():
+		kingpin.FatalIfError(runTop(deviceIds(), time.Duration(*top_delay)*time.Second), "")
+	case topo.FullCommand():
+		selection, err := exporter.QuerySelection(deviceIds())
+		kingpin.FatalIfError(err, "")
+		chips, err := exporter.QueryTopology()
+		kingpin.FatalIfError(err, "")
+		kingpin.FatalIfError(printTopology(os.Stdout, chips, selection), "")
+	case affinity.FullCommand():
+		a, err := queryAffinity(deviceIds())
+		kingpin.FatalIfError(err, "")
+		if len(*affinity_cmd) > 0 {
+			kingpin.FatalIfError(execPinned(a, *affinity_cmd), "")
+		}
+		printAffinity(os.Stdout, *affinity_out, a)
+	case pmon.FullCommand():
+		kingpin.FatalIfError(runPmon(os.Stdout, *proc_root, deviceIds(), time.Duration(*pmon_delay)*time.Second, *pmon_count), "")
+	case events.FullCommand():
+		kingpin.FatalIfError(runEvents(os.Stdout, deviceIds(), time.Duration(*events_delay)*time.Second, *events_count), "")
+	case diag.FullCommand():
+		results, err := exporter.RunDiag(exporter.DiagOptions{Level: *diag_level, Duration: *diag_duration})
+		kingpin.FatalIfError(err, "")
+		kingpin.FatalIfError(printDiag(os.Stdout, results), "")
+		os.Exit(int(exporter.WorstVerdict(results)))
+	case labels.FullCommand():
+		nodeLabels, err := exporter.QueryNodeLabels()
+		kingpin.FatalIfError(err, "")
+		if *labels_file != "" {
+			kingpin.FatalIfError(writeLabelsFile(*labels_file, *labels_out, nodeLabels), "")
+		} else {
+			kingpin.FatalIfError(printLabels(os.Stdout, *labels_out, nodeLabels), "")
+		}
+	case cdi_generate.FullCommand():
+		kingpin.FatalIfError(generateCDISpec(*cdi_output, *cdi_lib_dirs), "")
+	case hook.FullCommand():
+		kingpin.FatalIfError(runHook(os.Stdin, *hook_bundle), "")
+	case runtime_cmd.FullCommand():
+		kingpin.FatalIfError(runRuntime(*runtime_path, *runtime_args), "")
+	case plugin.FullCommand():
+		kingpin.FatalIfError(runDevicePlugin(*plugin_dir, *plugin_name, *plugin_health), "")
+	case check.FullCommand():
+		result := runCheck(deviceIds(), *check_warning, *check_critical)
+		fmt.Println(result)
+		os.Exit(int(result.State))
+	case info.FullCommand():
+		runInfo()
+	}
+}
+
+// terminate exits with 3, UNKNOWN for Nagios, when check fails on a usage error instead of reporting a result.
+func terminate(code int) {
+	if code != 0 {
+		if ctx, _ := kingpin.CommandLine.ParseContext(os.Args[1:]); ctx != nil && ctx.SelectedCommand == check {
+			code = int(exporter.CheckUnknown)
+		}
+	}
+	os.Exit(code)
+}
+
+// sysfsSupports reports whether command only needs the PCI devices, which the sysfs source provides.
+func sysfsSupports(command string) bool {
+	if *device_ids != "" {
+		return false
+	}
+	switch command {
+	case topo.FullCommand(), affinity.FullCommand(), cdi_generate.FullCommand(), hook.FullCommand(), runtime_cmd.FullCommand():
+		return true
+	case info.FullCommand():
+		needsSmi := *query || *list_apus || *influx_url != "" || isInfluxFormat(*format) || len(*query_apu) > 0 || len(*query_apps) > 0
+		return !needsSmi && (*chip_count || *chip_list)
+	}
+	return false
+}
+
+func deviceIds() []string {
+	return exporter.ParseDeviceIds(*device_ids)
+}
 
+func runInfo() {
 	switch {
+	case *query && *device_ids != "":
+		if *board_id != "" || *chip_id != "" {
+			kingpin.Fatalf("--id cannot be combined with --index and --chip_id")
+		}
+		detail, err := queryDetailBySelection(deviceIds())
+		kingpin.FatalIfError(err, "")
+		fmt.Print(detail)
 	case *query:
-		switch {
-		case *board_id != "" && *chip_id != "":
-			boardIndex, err := strconv.Atoi(*board_id)
+		var boardIndex, chipId *int
+		if *board_id != "" {
+			index, err := strconv.Atoi(*board_id)
 			if err != nil {
-				kingpin.Errorf("board index (%s)is not int value", *board_id)
+				kingpin.Fatalf("board index (%s)is not int value", *board_id)
 			}
-			chipId, err := strconv.Atoi(*chip_id)
-			if err != nil {
-				kingpin.Errorf("chip id (%s)is not int value", *chip_id)
+			if index < 0 {
+				kingpin.Fatalf("board index must be greater than 0.")
 			}
-			if boardIndex < 0 && chipId < 0 {
-				kingpin.Errorf("board index And chip id must be greater than 0.")
-			} else {
-				exporter.QueryLynSmiDetailInfoByChipIDAndBoardId(&boardIndex, &chipId)
-			}
-		case *board_id != "":
-			boardIndex, err := strconv.Atoi(*board_id)
+			boardIndex = &index
+		}
+		if *chip_id != "" {
+			id, err := strconv.Atoi(*chip_id)
 			if err != nil {
-				kingpin.Errorf("board index (%s)is not int value", *board_id)
+				kingpin.Fatalf("chip id (%s)is not int value", *chip_id)
 			}
-			if boardIndex < 0 {
-				kingpin.Errorf("board index must be greater than 0.")
-			} else {
-				exporter.QueryLynSmiDetailInfoByBoardId(&boardIndex)
+			if id < 0 {
+				kingpin.Fatalf("chip id must be greater than 0.")
 			}
-		case *chip_id != "":
-			kingpin.Errorf("board index is requested")
-		default:
-			exporter.QueryLynSmiDetailInfo()
+			chipId = &id
 		}
+		detail, err := exporter.QueryDetailInfo(boardIndex, chipId)
+		kingpin.FatalIfError(err, "")
+		fmt.Print(detail)
 	case *list_apus:
-		exporter.ListAPUs()
+		selection, err := exporter.QuerySelection(deviceIds())
+		kingpin.FatalIfError(err, "")
+		boards, err := exporter.ListBoards()
+		kingpin.FatalIfError(err, "")
+		printBoardList(os.Stdout, selectBoardSummary(boards, selection))
+	case *influx_url != "" || isInfluxFormat(*format):
+		if len(*query_apps) > 0 {
+			kingpin.Fatalf("--query-compute-apps does not support the influx format")
+		}
+		kingpin.FatalIfError(runInflux(os.Stdout, deviceIds(), *influx_url, *influx_token, *influx_batch, loopInterval()), "")
 	case len(*query_apu) > 0:
-		switch {
-		case len(*query_apu) < 0:
-			kingpin.Usage()
+		outFormat, err := parseOutputFormat(*format)
+		kingpin.FatalIfError(err, "")
+		kingpin.FatalIfError(checkLoopFormat(outFormat, loopInterval()), "")
+		fields, err := exporter.ParseQueryFields(*query_apu)
+		kingpin.FatalIfError(err, "")
+		sample := func() error {
+			boards, err := exporter.QueryBoards()
+			if err != nil {
+				return err
+			}
+			boards, selection, err := selectBoardBaseInfo(boards, deviceIds())
+			if err != nil {
+				return err
+			}
+			err = printAPUsInfo(os.Stdout, outFormat, fields, boards, selection)
+			outFormat.noheader = true
+			return err
+		}
+		kingpin.FatalIfError(runLoop(loopInterval(), 0, sample), "")
+	case len(*query_apps) > 0:
+		outFormat, err := parseOutputFormat(*format)
+		kingpin.FatalIfError(err, "")
+		kingpin.FatalIfError(checkLoopFormat(outFormat, loopInterval()), "")
+		fields, err := exporter.ParseComputeAppFields(*query_apps)
+		kingpin.FatalIfError(err, "")
+		sample := func() error {
+			processes, err := queryChipProcesses(*proc_root, deviceIds())
+			if err != nil {
+				return err
+			}
+			err = printComputeApps(os.Stdout, outFormat, fields, processes)
+			outFormat.noheader = true
+			return err
 		}
-		exporter.QueryLynAPUsInfo(*query_apu)
+		kingpin.FatalIfError(runLoop(loopInterval(), 0, sample), "")
 	case *chip_count:
-		exporter.QueryLynChipTotalNum()
+		count, err := exporter.QueryChipTotalNum()
+		kingpin.FatalIfError(err, "")
+		fmt.Printf("ChipTotalNumbyPci: %d\n", count)
 	case *chip_list:
-		exporter.QueryLynChipList()
+		chips, err := exporter.QueryChipList()
+		kingpin.FatalIfError(err, "")
+		printLines(os.Stdout, chips)
 	case *help_query_apu:
-		exporter.QueryAPUHelpInfo()
+		printFieldHelp(os.Stdout, exporter.QueryFieldHelp())
 	case *board_id != "" || *chip_id != "":
 		kingpin.Usage()
+	case *device_ids != "":
+		kingpin.Fatalf("--id is supported by --query, --query-apu, --query-compute-apps, --format=influx, --list-apus, dmon, pmon, top, topo, affinity, events and check")
 	default:
-		exporter.QueryLynSmiInfo()
+		interval := loopInterval()
+		sample := func() error {
+			smiInfo, err := exporter.QuerySmiInfo()
+			if err != nil {
+				return err
+			}
+			if interval > 0 {
+				fmt.Println(time.Now().Format(__LOOP_TIME_FORMAT__))
+			}
+			fmt.Print(smiInfo)
+			return nil
+		}
+		kingpin.FatalIfError(runLoop(interval, 0, sample), "")
 	}
 }
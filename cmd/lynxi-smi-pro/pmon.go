@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"time"
+)
+
+// __CONTAINER_ID_LEN__ is the length container IDs are shortened to, like docker ps does.
+const __CONTAINER_ID_LEN__ = 12
+
+func shortContainerId(id string) string {
+	if id == "" {
+		return __DMON_NO_VALUE__
+	}
+	if len(id) > __CONTAINER_ID_LEN__ {
+		return id[:__CONTAINER_ID_LEN__]
+	}
+	return id
+}
+
+func writePmonHeader(w io.Writer) {
+	fmt.Fprintf(w, "%s%4s %8s %-12s %-16s %s\n", __DMON_COMMENT__, "chip", "pid", "user", "command", "container")
+	fmt.Fprintf(w, "%s%4s %8s %-12s %-16s %s\n", __DMON_COMMENT__, "Idx", "#", "name", "name", "id")
+}
+
+func writePmonRows(w io.Writer, processes []exporter.ChipProcess) {
+	for _, p := range processes {
+		fmt.Fprintf(w, " %4d %8d %-12s %-16s %s\n", p.ChipIndex, p.Pid, p.User, p.Name, shortContainerId(p.ContainerId))
+	}
+}
+
+func runPmon(w io.Writer, procRoot string, ids []string, delay time.Duration, count int) error {
+	if delay <= 0 {
+		return fmt.Errorf("delay must be greater than 0")
+	}
+	writePmonHeader(w)
+	return runLoop(delay, count, func() error {
+		processes, err := queryChipProcesses(procRoot, ids)
+		if err != nil {
+			return err
+		}
+		writePmonRows(w, processes)
+		return nil
+	})
+}
+
+func printComputeApps(w io.Writer, format outputFormat, fields []string, processes []exporter.ChipProcess) error {
+	r := records{fields: fields, units: make([]string, len(fields))}
+	for _, p := range processes {
+		r.rows = append(r.rows, exporter.ComputeAppFieldValues(p, fields))
+	}
+	return format.write(w, r)
+}
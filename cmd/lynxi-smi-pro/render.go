@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"strconv"
+)
+
+const (
+	__FIELD_SEP__ = ", "
+	__COMMA_SEP__ = ","
+)
+
+func printAPUsInfo(w io.Writer, format outputFormat, fields []string, boards []exporter.BoardBaseInfo, selection *exporter.DeviceSelection) error {
+	r := records{fields: fields, units: make([]string, len(fields))}
+	for i, f := range fields {
+		r.units[i] = exporter.FieldUnit(f)
+	}
+	for _, board := range boards {
+		r.rows = append(r.rows, exporter.QuerySelectedFieldValues(board, fields, selection))
+	}
+	return format.write(w, r)
+}
+
+func printBoardList(w io.Writer, boards []exporter.BoardSummary) {
+	for _, b := range boards {
+		fmt.Fprintf(w, "APU %d:%s  (SN: %s, ChipCount: %d)\n", b.BoardIndex, b.ProductName, b.SerialNumber, b.ChipCount)
+	}
+}
+
+func printFieldHelp(w io.Writer, fieldHelpList []exporter.FieldHelp) {
+	fmt.Fprint(w, "List of valid properties to query for the switch query-apu:\n\n")
+	for _, v := range fieldHelpList {
+		fmt.Fprintln(w, strconv.Quote(v.Name))
+		fmt.Fprintln(w, v.Description)
+		fmt.Fprintln(w)
+	}
+}
+
+func printLines(w io.Writer, lines []string) {
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"lynxi_smi_pro/pkg/exporter"
+	"strconv"
+	"strings"
+)
+
+// queryDetailBySelection returns the lynxi-smi -q report of the selected boards, chip by chip for partly selected boards.
+func queryDetailBySelection(ids []string) (string, error) {
+	boards, err := exporter.QueryBoardMetrics()
+	if err != nil {
+		return "", err
+	}
+	selection, err := exporter.SelectDevices(boards, ids)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, b := range boards {
+		boardIndex := b.BoardIndex
+		if selection.HasAllChips(b) {
+			detail, err := exporter.QueryDetailInfo(&boardIndex, nil)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(detail)
+			continue
+		}
+		for i, c := range b.Chips {
+			if !selection.HasChip(c.ChipIndex) {
+				continue
+			}
+			chipId := i
+			detail, err := exporter.QueryDetailInfo(&boardIndex, &chipId)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(detail)
+		}
+	}
+	return sb.String(), nil
+}
+
+// selectBoardBaseInfo keeps the boards with a selected chip and returns the selection, nil without ids. --query-apu
+// reports the per chip fields of the chips that are not selected as N/A.
+func selectBoardBaseInfo(boards []exporter.BoardBaseInfo, ids []string) ([]exporter.BoardBaseInfo, *exporter.DeviceSelection, error) {
+	if len(ids) == 0 {
+		return boards, nil, nil
+	}
+	selection, err := exporter.SelectDevices(exporter.NewBoardMetricsList(boards), ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	var selected []exporter.BoardBaseInfo
+	for _, b := range boards {
+		if index, err := strconv.Atoi(b.BoardIndex); err == nil && selection.HasBoard(index) {
+			selected = append(selected, b)
+		}
+	}
+	return selected, selection, nil
+}
+
+func selectBoardSummary(boards []exporter.BoardSummary, selection *exporter.DeviceSelection) []exporter.BoardSummary {
+	var selected []exporter.BoardSummary
+	for _, b := range boards {
+		if selection.HasBoard(b.BoardIndex) {
+			selected = append(selected, b)
+		}
+	}
+	return selected
+}
+
+func selectProcesses(processes []exporter.ChipProcess, selection *exporter.DeviceSelection) []exporter.ChipProcess {
+	var selected []exporter.ChipProcess
+	for _, p := range processes {
+		if selection.HasChip(p.ChipIndex) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// queryBoardMetrics returns the selected boards and chips.
+func queryBoardMetrics(ids []string) ([]exporter.BoardMetrics, error) {
+	boards, err := exporter.QueryBoardMetrics()
+	if err != nil {
+		return nil, err
+	}
+	selection, err := exporter.SelectDevices(boards, ids)
+	if err != nil {
+		return nil, err
+	}
+	return selection.FilterBoards(boards), nil
+}
+
+// queryChipProcesses returns the processes using the selected chips.
+func queryChipProcesses(procRoot string, ids []string) ([]exporter.ChipProcess, error) {
+	selection, err := exporter.QuerySelection(ids)
+	if err != nil {
+		return nil, err
+	}
+	processes, err := exporter.QueryChipProcesses(procRoot)
+	if err != nil {
+		return nil, err
+	}
+	return selectProcesses(processes, selection), nil
+}
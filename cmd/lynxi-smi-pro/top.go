@@ -0,0 +1,318 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	__TOP_BAR_WIDTH__     = 20
+	__TOP_BAR_FULL__      = "#"
+	__TOP_BAR_EMPTY__     = "."
+	__TOP_TIME_FORMAT__   = "15:04:05"
+	__TOP_NO_VALUE__      = "N/A"
+	__TOP_KEYS_HELP__     = "←/→ board  s sort  space pause  q quit"
+	__ANSI_ALT_SCREEN__   = "\x1b[?1049h\x1b[?25l"
+	__ANSI_MAIN_SCREEN__  = "\x1b[?25h\x1b[?1049l"
+	__ANSI_CLEAR_SCREEN__ = "\x1b[H\x1b[2J"
+	__TERM_LINE_FEED__    = "\r\n"
+)
+
+const (
+	__KEY_CTRL_C__ = "\x03"
+	__KEY_ESC__    = "\x1b"
+	__KEY_TAB__    = "\t"
+	__KEY_RIGHT__  = "\x1b[C"
+	__KEY_LEFT__   = "\x1b[D"
+)
+
+// topSort orders the chips of the selected board, descending for the metrics.
+type topSort struct {
+	name string
+	less func(a, b exporter.ChipMetrics) bool
+}
+
+func percentVal(v *exporter.Percent) float64 {
+	if v == nil {
+		return -1
+	}
+	return float64(*v)
+}
+
+var topSorts = []topSort{
+	{"chip", func(a, b exporter.ChipMetrics) bool { return a.ChipIndex < b.ChipIndex }},
+	{"apu", func(a, b exporter.ChipMetrics) bool {
+		return percentVal(a.Utilization.Apu) > percentVal(b.Utilization.Apu)
+	}},
+	{"memory", func(a, b exporter.ChipMetrics) bool {
+		return percentVal(a.Utilization.Memory) > percentVal(b.Utilization.Memory)
+	}},
+	{"temperature", func(a, b exporter.ChipMetrics) bool {
+		if a.Temperature == nil || b.Temperature == nil {
+			return b.Temperature == nil && a.Temperature != nil
+		}
+		return *a.Temperature > *b.Temperature
+	}},
+}
+
+// topView is the state of the top command, it is rendered after every sample and key press.
+type topView struct {
+	boards  []exporter.BoardMetrics
+	board   int
+	sort    int
+	paused  bool
+	err     error
+	updated time.Time
+}
+
+func (v *topView) update(boards []exporter.BoardMetrics, err error, now time.Time) {
+	if v.paused {
+		return
+	}
+	v.err = err
+	if err != nil {
+		return
+	}
+	v.boards = boards
+	v.updated = now
+	if v.board >= len(boards) {
+		v.board = 0
+	}
+}
+
+// handleKey applies a key press and returns true when the view is to be closed.
+func (v *topView) handleKey(key string) bool {
+	switch key {
+	case "q", __KEY_CTRL_C__, __KEY_ESC__:
+		return true
+	case __KEY_RIGHT__, __KEY_TAB__, "n", "l":
+		if len(v.boards) > 0 {
+			v.board = (v.board + 1) % len(v.boards)
+		}
+	case __KEY_LEFT__, "b", "h":
+		if len(v.boards) > 0 {
+			v.board = (v.board + len(v.boards) - 1) % len(v.boards)
+		}
+	case "s":
+		v.sort = (v.sort + 1) % len(topSorts)
+	case " ", "p":
+		v.paused = !v.paused
+	}
+	return false
+}
+
+// bar draws val relative to max, e.g. [#####...............].
+func bar(val *float64, max float64) string {
+	filled := 0
+	if val != nil && max > 0 {
+		filled = int(*val / max * __TOP_BAR_WIDTH__)
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > __TOP_BAR_WIDTH__ {
+		filled = __TOP_BAR_WIDTH__
+	}
+	return "[" + strings.Repeat(__TOP_BAR_FULL__, filled) + strings.Repeat(__TOP_BAR_EMPTY__, __TOP_BAR_WIDTH__-filled) + "]"
+}
+
+// optionalFloat converts the unit types of the metrics model, nil stays nil.
+func optionalFloat(v interface{}) *float64 {
+	var f float64
+	switch v := v.(type) {
+	case *float64:
+		return v
+	case *exporter.Percent:
+		if v != nil {
+			f = float64(*v)
+			return &f
+		}
+	case *exporter.Watts:
+		if v != nil {
+			f = float64(*v)
+			return &f
+		}
+	case *exporter.MHz:
+		if v != nil {
+			f = float64(*v)
+			return &f
+		}
+	case *exporter.Celsius:
+		if v != nil {
+			f = float64(*v)
+			return &f
+		}
+	case *exporter.Volts:
+		if v != nil {
+			f = float64(*v)
+			return &f
+		}
+	case *uint64:
+		if v != nil {
+			f = float64(*v)
+			return &f
+		}
+	}
+	return nil
+}
+
+// formatVal prints v with prec decimals, N/A when it is not reported.
+func formatVal(v interface{}, prec int) string {
+	f := optionalFloat(v)
+	if f == nil {
+		return __TOP_NO_VALUE__
+	}
+	return fmt.Sprintf("%.*f", prec, *f)
+}
+
+func percentBar(name string, v *exporter.Percent) string {
+	return fmt.Sprintf("%-3s %s %4s %%", name, bar(optionalFloat(v), 100), formatVal(v, 0))
+}
+
+func clockVal(name string, cur *exporter.MHz, max *exporter.MHz) string {
+	maxVal := optionalFloat(max)
+	limit := 0.0
+	if maxVal != nil {
+		limit = *maxVal
+	}
+	return fmt.Sprintf("%-3s clk %s %5s / %5s MHz", name, bar(optionalFloat(cur), limit), formatVal(cur, 0), formatVal(max, 0))
+}
+
+func (v *topView) lines() []string {
+	status := "running"
+	if v.paused {
+		status = "paused"
+	}
+	lines := []string{fmt.Sprintf("lynxi-smi-pro top - %s  %s  sort: %s  %s",
+		v.updated.Format(__TOP_TIME_FORMAT__), status, topSorts[v.sort].name, __TOP_KEYS_HELP__)}
+	if v.err != nil {
+		lines = append(lines, "error: "+v.err.Error())
+	}
+	if len(v.boards) == 0 {
+		return append(lines, "", "No APU found.")
+	}
+	tabs := make([]string, len(v.boards))
+	for i, b := range v.boards {
+		tabs[i] = fmt.Sprintf(" %d ", b.BoardIndex)
+		if i == v.board {
+			tabs[i] = fmt.Sprintf("[%d]", b.BoardIndex)
+		}
+	}
+	b := v.boards[v.board]
+	powerLimit := 0.0
+	if limit := optionalFloat(b.PowerLimit); limit != nil {
+		powerLimit = *limit
+	}
+	lines = append(lines,
+		"Boards:"+strings.Join(tabs, ""),
+		"",
+		fmt.Sprintf("Board %d  %s  SN %s  Driver %s  Firmware %s  Chips %d",
+			b.BoardIndex, b.ProductName, b.SerialNumber, b.DriverVersion, b.FirmwareVersion, b.ChipCount),
+		fmt.Sprintf("PWR %s %6s / %6s W  Input %s V  ECC corrected %s uncorrected %s",
+			bar(optionalFloat(b.PowerDraw), powerLimit), formatVal(b.PowerDraw, 2), formatVal(b.PowerLimit, 2),
+			formatVal(b.VoltageInput, 2), formatVal(b.EccErrorsCorrected, 0), formatVal(b.EccErrorsUncorrected, 0)),
+		percentBar("APU", b.Utilization.Apu)+"  "+percentBar("CPU", b.Utilization.Cpu)+"  "+
+			percentBar("VIC", b.Utilization.Vic)+"  "+percentBar("MEM", b.Utilization.Memory))
+	chips := make([]exporter.ChipMetrics, len(b.Chips))
+	copy(chips, b.Chips)
+	sort.SliceStable(chips, func(i, j int) bool { return topSorts[v.sort].less(chips[i], chips[j]) })
+	for _, c := range chips {
+		lines = append(lines, "",
+			fmt.Sprintf("Chip %d  %s C  %s V  IPE %s fps  ECC %s  corrected %s uncorrected %s",
+				c.ChipIndex, formatVal(c.Temperature, 0), formatVal(c.Voltage, 2), formatVal(c.IpeFps, 0),
+				c.EccMode, formatVal(c.EccErrorsCorrected, 0), formatVal(c.EccErrorsUncorrected, 0)),
+			"  "+percentBar("APU", c.Utilization.Apu)+"  "+percentBar("CPU", c.Utilization.Cpu),
+			"  "+percentBar("VIC", c.Utilization.Vic)+"  "+percentBar("MEM", c.Utilization.Memory),
+			"  "+clockVal("APU", c.Clocks.Apu, c.Clocks.ApuMax),
+			"  "+clockVal("CPU", c.Clocks.Cpu, c.Clocks.CpuMax),
+			"  "+clockVal("MEM", c.Clocks.Memory, c.Clocks.MemoryMax))
+	}
+	return lines
+}
+
+// render writes a frame cut to the size of the terminal.
+func (v *topView) render(w io.Writer, width int, height int) error {
+	lines := v.lines()
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, line := range lines {
+		if width > 0 && len([]rune(line)) > width {
+			lines[i] = string([]rune(line)[:width])
+		}
+	}
+	_, err := io.WriteString(w, __ANSI_CLEAR_SCREEN__+strings.Join(lines, __TERM_LINE_FEED__))
+	return err
+}
+
+func readKeys(r io.Reader, keys chan<- string) {
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		keys <- string(buf[:n])
+	}
+}
+
+func runTop(ids []string, delay time.Duration) error {
+	if delay <= 0 {
+		return errors.New("delay must be greater than 0")
+	}
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return errors.New("top requires a terminal, use dmon or --query-apu with --loop otherwise")
+	}
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(in, state)
+	fmt.Fprint(os.Stdout, __ANSI_ALT_SCREEN__)
+	defer fmt.Fprint(os.Stdout, __ANSI_MAIN_SCREEN__)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+
+	view := &topView{}
+	sample := func() {
+		boards, err := queryBoardMetrics(ids)
+		view.update(boards, err, time.Now())
+	}
+	sample()
+	for {
+		width, height, err := term.GetSize(out)
+		if err != nil {
+			width, height = 0, 0
+		}
+		if err := view.render(os.Stdout, width, height); err != nil {
+			return err
+		}
+		select {
+		case <-signals:
+			return nil
+		case key, ok := <-keys:
+			if !ok || view.handleKey(key) {
+				return nil
+			}
+		case <-ticker.C:
+			sample()
+		}
+	}
+}
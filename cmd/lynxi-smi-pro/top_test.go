@@ -0,0 +1,69 @@
+package main
+
+import (
+	"lynxi_smi_pro/pkg/exporter"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBoards() []exporter.BoardMetrics {
+	percent := func(v float64) *exporter.Percent { p := exporter.Percent(v); return &p }
+	celsius := func(v float64) *exporter.Celsius { c := exporter.Celsius(v); return &c }
+	watts := func(v float64) *exporter.Watts { w := exporter.Watts(v); return &w }
+	return []exporter.BoardMetrics{
+		{BoardIndex: 0, ProductName: "HP300", SerialNumber: "2203A0012", ChipCount: 2, PowerDraw: watts(37.5), PowerLimit: watts(75),
+			Chips: []exporter.ChipMetrics{
+				{ChipIndex: 0, Temperature: celsius(45), Utilization: exporter.Utilization{Apu: percent(10)}},
+				{ChipIndex: 1, Temperature: celsius(60), Utilization: exporter.Utilization{Apu: percent(90)}},
+			}},
+		{BoardIndex: 1, ProductName: "HP300", SerialNumber: "2203A0013", ChipCount: 1,
+			Chips: []exporter.ChipMetrics{{ChipIndex: 2}}},
+	}
+}
+
+func TestTopView(t *testing.T) {
+	v := &topView{}
+	v.update(testBoards(), nil, time.Date(2022, 5, 1, 12, 30, 0, 0, time.UTC))
+	lines := strings.Join(v.lines(), "\n")
+	for _, want := range []string{
+		"lynxi-smi-pro top - 12:30:00  running  sort: chip",
+		"Boards:[0] 1 ",
+		"PWR [##########..........]  37.50 /  75.00 W",
+		"Chip 0  45 C  N/A V",
+		"  APU [##..................]   10 %",
+		"  APU clk [....................]   N/A /   N/A MHz",
+	} {
+		if !strings.Contains(lines, want) {
+			t.Errorf("view does not contain %q:\n%s", want, lines)
+		}
+	}
+	if strings.Index(lines, "Chip 0") > strings.Index(lines, "Chip 1") {
+		t.Errorf("chips are not sorted by index:\n%s", lines)
+	}
+
+	v.handleKey("s")
+	lines = strings.Join(v.lines(), "\n")
+	if !strings.Contains(lines, "sort: apu") || strings.Index(lines, "Chip 1") > strings.Index(lines, "Chip 0") {
+		t.Errorf("chips are not sorted by APU utilization:\n%s", lines)
+	}
+
+	v.handleKey(__KEY_RIGHT__)
+	lines = strings.Join(v.lines(), "\n")
+	if !strings.Contains(lines, "Boards: 0 [1]") || !strings.Contains(lines, "Board 1  HP300  SN 2203A0013") {
+		t.Errorf("board 1 is not selected:\n%s", lines)
+	}
+	v.handleKey(__KEY_RIGHT__)
+	if v.board != 0 {
+		t.Errorf("board = %d after wrapping around, want 0", v.board)
+	}
+
+	v.handleKey(" ")
+	v.update(nil, nil, time.Now())
+	if !v.paused || len(v.boards) != 2 {
+		t.Errorf("paused view was updated: paused %v, %d boards", v.paused, len(v.boards))
+	}
+	if !v.handleKey("q") {
+		t.Error("q does not quit")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"lynxi_smi_pro/pkg/exporter"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+func chipName(chipIndex int) string {
+	return exporter.ChipIdPrefix + strconv.Itoa(chipIndex)
+}
+
+// printTopology writes the matrix of the connections between the chips with their CPU and NUMA affinity.
+func printTopology(w io.Writer, chips []exporter.ChipTopology, selection *exporter.DeviceSelection) error {
+	var selected []exporter.ChipTopology
+	for _, c := range chips {
+		if selection.HasChip(c.ChipIndex) {
+			selected = append(selected, c)
+		}
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{""}
+	for _, c := range selected {
+		header = append(header, chipName(c.ChipIndex))
+	}
+	header = append(header, "CPU Affinity", "NUMA Affinity")
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, a := range selected {
+		row := []string{chipName(a.ChipIndex)}
+		for _, b := range selected {
+			row = append(row, string(exporter.ChipLink(a, b)))
+		}
+		cpuList, numaNode := __TOP_NO_VALUE__, __TOP_NO_VALUE__
+		if a.CPUList != "" {
+			cpuList = a.CPUList
+		}
+		if a.NumaNode != nil {
+			numaNode = strconv.Itoa(*a.NumaNode)
+		}
+		row = append(row, cpuList, numaNode)
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprint(w, "\nLegend:\n\n")
+	for _, l := range exporter.Links {
+		fmt.Fprintf(w, "  %-4s = %s\n", l.Link, l.Description)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"io"
+	"io/ioutil"
+	"lynxi_smi_pro/internal/simulator"
+	"lynxi_smi_pro/pkg/exporter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lynxi-smi-sim emulates lynxi-smi, lspci and dpkg when it is invoked through a link with their name.
+func main() {
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	var err error
+	switch filepath.Base(os.Args[0]) {
+	case exporter.DefaultLynSmiCommand:
+		err = runLynSmi(w, os.Args[1:])
+	case exporter.DefaultPCICommand:
+		err = runLspci(w, os.Args[1:])
+	case exporter.DefaultFindLynDriverCommand:
+		err = runDpkg(w, os.Args[1:])
+	default:
+		err = runSim(w, os.Args[1:])
+	}
+	if err != nil {
+		w.Flush()
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func optionalInt(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func runLynSmi(w io.Writer, args []string) error {
+	app := kingpin.New(exporter.DefaultLynSmiCommand, "Simulated lynxi-smi.")
+	query := app.Flag("query", "Display APU detail info.").Short('q').Bool()
+	boardIndex := app.Flag("index", "Board index.").Short('i').String()
+	chipIndex := app.Flag("chip", "Chip index.").Short('c').String()
+	version := app.Flag("version", "Display version.").Short('v').Bool()
+	if _, err := app.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := simulator.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	board, err := optionalInt(*boardIndex)
+	if err != nil {
+		return err
+	}
+	chip, err := optionalInt(*chipIndex)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *version:
+		cfg.WriteSmiVersion(w)
+		return nil
+	case *query:
+		return cfg.WriteSmiDetail(w, board, chip)
+	}
+	return cfg.WriteSmiSummary(w)
+}
+
+func runLspci(w io.Writer, args []string) error {
+	app := kingpin.New(exporter.DefaultPCICommand, "Simulated lspci.")
+	device := app.Flag("device", "Show only devices with the vendor:device ID.").Short('d').String()
+	if _, err := app.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := simulator.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	if *device != "" && !strings.HasPrefix(simulator.VendorId+":"+simulator.DeviceId, strings.TrimSuffix(*device, ":")) {
+		return nil
+	}
+	cfg.WriteLspci(w)
+	return nil
+}
+
+// runDpkg accepts the arguments of "dpkg -l | grep -i <package>" as passed by lynxi-smi-pro.
+func runDpkg(w io.Writer, args []string) error {
+	cfg, err := simulator.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	var pattern []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") || arg == "|" || arg == "grep" {
+			continue
+		}
+		pattern = append(pattern, arg)
+	}
+	cfg.WriteDpkg(w, pattern...)
+	return nil
+}
+
+func runSim(w io.Writer, args []string) error {
+	app := kingpin.New("lynxi-smi-sim", "Simulate lynxi-smi, lspci, dpkg and sysfs for APU-less machines.")
+	boards := app.Flag("boards", "Number of simulated boards.").Envar(simulator.EnvBoards).Default(strconv.Itoa(simulator.DefaultBoards)).Int()
+	chips := app.Flag("chips", "Number of chips per board.").Envar(simulator.EnvChips).Default(strconv.Itoa(simulator.DefaultChips)).Int()
+	scenario := app.Flag("scenario", "Simulated scenario: "+strings.Join(simulator.Scenarios, ", ")+".").Envar(simulator.EnvScenario).Default(simulator.ScenarioNormal).Enum(simulator.Scenarios...)
+
+	install := app.Command("install", "Link lynxi-smi, lspci and dpkg to the simulator and write a sysfs tree below dir/sys.")
+	installDir := install.Arg("dir", "Directory to add in front of PATH.").Required().String()
+	sysfs := app.Command("sysfs", "Write the simulated sysfs tree.")
+	sysfsRoot := sysfs.Arg("root", "Root of the sysfs tree.").Required().String()
+	capture := app.Command("capture", "Write the outputs read by the replay data source of lynxi-smi-pro.")
+	captureDir := capture.Arg("dir", "Capture directory.").Required().String()
+
+	command, err := app.Parse(args)
+	if err != nil {
+		return err
+	}
+	cfg := simulator.Config{Boards: *boards, Chips: *chips, Scenario: *scenario}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	switch command {
+	case install.FullCommand():
+		return installLinks(w, cfg, *installDir)
+	case sysfs.FullCommand():
+		return cfg.WriteSysfs(*sysfsRoot)
+	case capture.FullCommand():
+		return writeCapture(cfg, *captureDir)
+	}
+	return nil
+}
+
+func installLinks(w io.Writer, cfg simulator.Config, dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range []string{exporter.DefaultLynSmiCommand, exporter.DefaultPCICommand, exporter.DefaultFindLynDriverCommand} {
+		link := filepath.Join(dir, name)
+		_ = os.Remove(link)
+		if err := os.Symlink(self, link); err != nil {
+			return err
+		}
+	}
+	sysfsRoot := filepath.Join(dir, "sys")
+	if err := cfg.WriteSysfs(sysfsRoot); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "export PATH=%s:$PATH %s\n", dir, strings.Join(cfg.Env(), " "))
+	fmt.Fprintf(w, "lynxi-smi-pro --sysfs-root=%s\n", sysfsRoot)
+	return nil
+}
+
+func writeCapture(cfg simulator.Config, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	outputs := map[string]func(w io.Writer) error{
+		exporter.ReplayFileName(exporter.DefaultLynSmiCommand): cfg.WriteSmiSummary,
+		exporter.ReplayFileName(exporter.DefaultLynSmiCommand, exporter.LynSmiDetailInfoCmdParam): func(w io.Writer) error {
+			return cfg.WriteSmiDetail(w, nil, nil)
+		},
+		exporter.ReplayFileName(exporter.DefaultLynSmiCommand, exporter.LynSmiVersionCmdParam): func(w io.Writer) error {
+			cfg.WriteSmiVersion(w)
+			return nil
+		},
+		exporter.ReplayFileName(exporter.DefaultPCICommand): func(w io.Writer) error {
+			cfg.WriteLspci(w)
+			return nil
+		},
+		exporter.ReplayFileName(exporter.DefaultFindLynDriverCommand): func(w io.Writer) error {
+			cfg.WriteDpkg(w)
+			return nil
+		},
+	}
+	for b := 0; b < cfg.Boards; b++ {
+		board := b
+		outputs[exporter.ReplayFileName(exporter.DefaultLynSmiCommand, exporter.LynSmiDetailInfoCmdParam,
+			exporter.LynSmiCardIdCmdParam, strconv.Itoa(b))] = func(w io.Writer) error {
+			return cfg.WriteSmiDetail(w, &board, nil)
+		}
+	}
+	for name, write := range outputs {
+		var sb strings.Builder
+		if err := write(&sb); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(sb.String()), 0644); err != nil {
+			return err
+		}
+	}
+	return cfg.WriteSysfs(filepath.Join(dir, "sys"))
+}
@@ -0,0 +1,428 @@
+package simulator
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ScenarioNormal  = "normal"
+	ScenarioNA      = "na"
+	ScenarioBanner  = "banner"
+	ScenarioHot     = "hot"
+	ScenarioEcc     = "ecc"
+	ScenarioMissing = "missing"
+)
+
+const (
+	EnvBoards   = "LYNXI_SIM_BOARDS"
+	EnvChips    = "LYNXI_SIM_CHIPS"
+	EnvScenario = "LYNXI_SIM_SCENARIO"
+)
+
+const (
+	DefaultBoards = 1
+	DefaultChips  = 3
+	Version       = "1.6.0"
+	SdkVersion    = "1.6.2"
+	VendorId      = "1e9f"
+	DeviceId      = "27c5"
+	SubDeviceId   = "0001"
+	FirstPciBus   = 3
+)
+
+const (
+	__NA__         = "NA"
+	__KEY_WIDTH__  = 31
+	__BANNER__     = "******************** lynxi-smi ********************\n"
+	__ERROR_LINE__ = "[ERROR] lynSmi.cpp:214 lynGetBoardInfo failed, SN not ready, retry\n"
+)
+
+var Scenarios = []string{ScenarioNormal, ScenarioNA, ScenarioBanner, ScenarioHot, ScenarioEcc, ScenarioMissing}
+
+// Config describes the simulated system: Boards boards with Chips chips each.
+type Config struct {
+	Boards   int
+	Chips    int
+	Scenario string
+}
+
+// ConfigFromEnv reads the configuration from the LYNXI_SIM_* environment variables.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Boards: DefaultBoards, Chips: DefaultChips, Scenario: ScenarioNormal}
+	var err error
+	if v := os.Getenv(EnvBoards); v != "" {
+		if cfg.Boards, err = strconv.Atoi(v); err != nil {
+			return cfg, fmt.Errorf("%s: %v", EnvBoards, err)
+		}
+	}
+	if v := os.Getenv(EnvChips); v != "" {
+		if cfg.Chips, err = strconv.Atoi(v); err != nil {
+			return cfg, fmt.Errorf("%s: %v", EnvChips, err)
+		}
+	}
+	if v := os.Getenv(EnvScenario); v != "" {
+		cfg.Scenario = v
+	}
+	return cfg, cfg.Validate()
+}
+
+func (c Config) Validate() error {
+	if c.Boards < 0 || c.Chips < 1 {
+		return fmt.Errorf("invalid configuration: %d boards with %d chips", c.Boards, c.Chips)
+	}
+	for _, s := range Scenarios {
+		if c.Scenario == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown scenario %q, expected one of %s", c.Scenario, strings.Join(Scenarios, ", "))
+}
+
+// Env returns the configuration as LYNXI_SIM_* environment variables.
+func (c Config) Env() []string {
+	return []string{
+		EnvBoards + "=" + strconv.Itoa(c.Boards),
+		EnvChips + "=" + strconv.Itoa(c.Chips),
+		EnvScenario + "=" + c.Scenario,
+	}
+}
+
+func (c Config) ChipTotal() int {
+	if c.Scenario == ScenarioMissing {
+		return 0
+	}
+	return c.Boards * c.Chips
+}
+
+func (c Config) ProductName() string {
+	return fmt.Sprintf("HP%d00", c.Chips)
+}
+
+func (c Config) SerialNumber(board int) string {
+	return fmt.Sprintf("2203A%04d", board+12)
+}
+
+func (c Config) ChipUuid(board int, chip int) string {
+	return fmt.Sprintf("%08x-4c59-4e58-%04x-%012x", 0x1e9f27c5, board, board*c.Chips+chip)
+}
+
+// PciAddress returns the bus address of the chip without PCI domain, e.g. 03:00.0.
+func (c Config) PciAddress(board int, chip int) string {
+	return fmt.Sprintf("%02x:00.0", FirstPciBus+board*c.Chips+chip)
+}
+
+// PciPath returns the sysfs directories of the chip below devices: a root bus per NUMA node, a root port per board and a
+// PCIe switch with a downstream port per chip, e.g. pci0000:00/0000:00:01.0/0000:40:00.0/0000:41:00.0/0000:03:00.0.
+func (c Config) PciPath(board int, chip int) []string {
+	rootBus := 0x80 * c.NumaNode(board)
+	switchBus := rootBus + 0x40 + 2*(board/2)
+	return []string{
+		fmt.Sprintf("pci0000:%02x", rootBus),
+		fmt.Sprintf("0000:%02x:%02x.0", rootBus, board/2+1),
+		fmt.Sprintf("0000:%02x:00.0", switchBus),
+		fmt.Sprintf("0000:%02x:%02x.0", switchBus+1, chip),
+		"0000:" + c.PciAddress(board, chip),
+	}
+}
+
+func (c Config) NumaNode(board int) int {
+	return board % 2
+}
+
+func (c Config) NumaCPUList(board int) string {
+	if c.NumaNode(board) == 0 {
+		return "0-15"
+	}
+	return "16-31"
+}
+
+func (c Config) val(v string) string {
+	if c.Scenario == ScenarioNA {
+		return __NA__
+	}
+	return v
+}
+
+func (c Config) temperature(chip int) int {
+	if c.Scenario == ScenarioHot {
+		return 97 + chip
+	}
+	return 45 + chip
+}
+
+// apuClock is throttled below the maximum of 1000 MHz when the chips are hot.
+func (c Config) apuClock() int {
+	if c.Scenario == ScenarioHot {
+		return 800
+	}
+	return 1000
+}
+
+func (c Config) eccErrors() (int, int) {
+	if c.Scenario == ScenarioEcc {
+		return 2, 1
+	}
+	return 0, 0
+}
+
+func (c Config) util(base int, chip int) int {
+	return base + 3*chip
+}
+
+func keyVal(indent int, key string, val interface{}) string {
+	return fmt.Sprintf("%s%-*s: %v\n", strings.Repeat(" ", indent), __KEY_WIDTH__-indent, key, val)
+}
+
+func (c Config) writeBanner(w io.Writer) {
+	if c.Scenario == ScenarioBanner {
+		fmt.Fprint(w, __BANNER__+__ERROR_LINE__)
+	}
+}
+
+// WriteSmiDetail writes the lynxi-smi -q report, board and chip narrow it down when not nil.
+func (c Config) WriteSmiDetail(w io.Writer, board *int, chip *int) error {
+	if err := c.checkDevice(board, chip); err != nil {
+		return err
+	}
+	c.writeBanner(w)
+	for b := 0; b < c.Boards; b++ {
+		if board != nil && *board != b {
+			continue
+		}
+		var chips []int
+		for i := 0; i < c.Chips; i++ {
+			if chip == nil || *chip == i {
+				chips = append(chips, i)
+			}
+		}
+		c.writeBoardDetail(w, b, chips)
+	}
+	return nil
+}
+
+func (c Config) checkDevice(board *int, chip *int) error {
+	if c.Scenario == ScenarioMissing {
+		return fmt.Errorf("[ERROR] lynSmi.cpp:88 open /dev/lynd0 failed, no such device")
+	}
+	if board != nil && (*board < 0 || *board >= c.Boards) {
+		return fmt.Errorf("[ERROR] lynSmi.cpp:102 invalid board index %d", *board)
+	}
+	if chip != nil && (*chip < 0 || *chip >= c.Chips) {
+		return fmt.Errorf("[ERROR] lynSmi.cpp:109 invalid chip index %d", *chip)
+	}
+	return nil
+}
+
+func (c Config) writeBoardDetail(w io.Writer, b int, chips []int) {
+	n := len(chips)
+	fmt.Fprintf(w, "Board: %d\n", b)
+	fmt.Fprint(w, keyVal(4, "Product Name", c.ProductName()))
+	fmt.Fprint(w, keyVal(4, "Product Brand", "Lynxi"))
+	fmt.Fprint(w, keyVal(4, "Product Number", "LX-"+c.ProductName()+"-01"))
+	fmt.Fprint(w, keyVal(4, "Driver Version", Version))
+	fmt.Fprint(w, keyVal(4, "Firmware Version", "2.1.0"))
+	fmt.Fprint(w, keyVal(4, "Serial Number", c.SerialNumber(b)))
+	fmt.Fprint(w, keyVal(4, "Chip Count", n))
+	fmt.Fprint(w, "    Chip ID\n")
+	for _, i := range chips {
+		fmt.Fprint(w, keyVal(8, fmt.Sprintf("Chip%d", i), b*c.Chips+i))
+	}
+	fmt.Fprint(w, "    UUID\n")
+	for _, i := range chips {
+		fmt.Fprint(w, keyVal(8, fmt.Sprintf("Chip%d", i), c.ChipUuid(b, i)))
+	}
+	fmt.Fprint(w, "    Utilization\n")
+	for _, engine := range []struct {
+		name string
+		base int
+	}{{"APU", 10}, {"CPU", 5}, {"VIC", 2}, {"Memory", 30}} {
+		fmt.Fprintf(w, "        %s\n", engine.name)
+		total := 0
+		for _, i := range chips {
+			total += c.util(engine.base, i)
+		}
+		fmt.Fprint(w, keyVal(12, "Total", c.val(fmt.Sprintf("%d %%", total/n))))
+		for _, i := range chips {
+			fmt.Fprint(w, keyVal(12, fmt.Sprintf("Chip%d", i), c.val(fmt.Sprintf("%d %%", c.util(engine.base, i)))))
+		}
+	}
+	fmt.Fprint(w, "        IPE-FPS\n")
+	fmt.Fprint(w, keyVal(12, "Total", c.val(strconv.Itoa(40*n))))
+	for _, i := range chips {
+		fmt.Fprint(w, keyVal(12, fmt.Sprintf("Chip%d", i), c.val("40")))
+	}
+	fmt.Fprint(w, "    PCIE\n")
+	for _, i := range chips {
+		fmt.Fprintf(w, "        Chip%d\n", i)
+		fmt.Fprint(w, keyVal(12, "Vendor ID", "0x"+VendorId))
+		fmt.Fprint(w, keyVal(12, "Device ID", "0x"+DeviceId))
+		fmt.Fprint(w, keyVal(12, "Sub Vendor ID", "0x"+VendorId))
+		fmt.Fprint(w, keyVal(12, "Sub Device ID", "0x"+SubDeviceId))
+		fmt.Fprint(w, keyVal(12, "PCIe Generation Max", "3"))
+		fmt.Fprint(w, keyVal(12, "PCIe Generation Current", c.val("3")))
+	}
+	fmt.Fprint(w, keyVal(4, "Fan Speed", __NA__))
+	fmt.Fprint(w, "    Temperature\n")
+	for _, i := range chips {
+		fmt.Fprintf(w, "        Chip%d\n", i)
+		fmt.Fprint(w, keyVal(12, "BIU Current Temp", c.val(fmt.Sprintf("%d C", c.temperature(i)))))
+		fmt.Fprint(w, keyVal(12, "BIU Slowdown Temp", "95 C"))
+		fmt.Fprint(w, keyVal(12, "BIU Shutdown Temp", "105 C"))
+	}
+	fmt.Fprint(w, "    Voltage\n")
+	fmt.Fprint(w, "        Chip Voltage\n")
+	for _, i := range chips {
+		fmt.Fprint(w, keyVal(12, fmt.Sprintf("Chip%d", i), c.val("0.80 V")))
+	}
+	fmt.Fprint(w, "        Board Voltage\n")
+	fmt.Fprint(w, keyVal(12, "Input", c.val("12.00 V")))
+	fmt.Fprint(w, "    Clocks\n")
+	for _, i := range chips {
+		fmt.Fprintf(w, "        Chip%d\n", i)
+		fmt.Fprint(w, keyVal(12, "APU Clock", c.val(fmt.Sprintf("%d MHz", c.apuClock()))))
+		fmt.Fprint(w, keyVal(12, "APU Max Clock", "1000 MHz"))
+		fmt.Fprint(w, keyVal(12, "CPU Clock", c.val("1500 MHz")))
+		fmt.Fprint(w, keyVal(12, "CPU Max Clock", "1500 MHz"))
+		fmt.Fprint(w, keyVal(12, "Memory Clock", c.val("2133 MHz")))
+		fmt.Fprint(w, keyVal(12, "Memory Max Clock", "2133 MHz"))
+	}
+	fmt.Fprint(w, keyVal(4, "Power Draw", c.val(fmt.Sprintf("%.2f W", 10.5*float64(n)))))
+	fmt.Fprint(w, keyVal(4, "Power Limit", fmt.Sprintf("%.2f W", 25*float64(n))))
+	fmt.Fprint(w, "    ECC Mode\n")
+	for _, i := range chips {
+		fmt.Fprint(w, keyVal(8, fmt.Sprintf("Chip%d", i), c.val("Enabled")))
+	}
+	corrected, uncorrected := c.eccErrors()
+	fmt.Fprint(w, "    DDR ECC Err Count\n")
+	fmt.Fprint(w, "        Total\n")
+	fmt.Fprint(w, keyVal(12, "Corrected Err", c.val(strconv.Itoa(corrected*n))))
+	fmt.Fprint(w, keyVal(12, "Uncorrected Err", c.val(strconv.Itoa(uncorrected*n))))
+	for _, i := range chips {
+		fmt.Fprintf(w, "        Chip%d\n", i)
+		fmt.Fprint(w, keyVal(12, "Corrected Err", c.val(strconv.Itoa(corrected))))
+		fmt.Fprint(w, keyVal(12, "Uncorrected Err", c.val(strconv.Itoa(uncorrected))))
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// WriteSmiSummary writes the output of lynxi-smi without arguments.
+func (c Config) WriteSmiSummary(w io.Writer) error {
+	if err := c.checkDevice(nil, nil); err != nil {
+		return err
+	}
+	const sep = "+-------+----------+------------+------+-------+----------+-----------+\n"
+	c.writeBanner(w)
+	fmt.Fprintf(w, "Product Name: %s          Driver Version: %s\n", Version, Version)
+	fmt.Fprint(w, sep)
+	fmt.Fprint(w, "| Board | Name     | SN         | Chip | Temp  | APU-Util | Power     |\n")
+	fmt.Fprint(w, sep)
+	for b := 0; b < c.Boards; b++ {
+		for i := 0; i < c.Chips; i++ {
+			fmt.Fprintf(w, "| %-5d | %-8s | %-10s | %-4d | %-5s | %-8s | %-9s |\n", b, c.ProductName(), c.SerialNumber(b), i,
+				c.val(fmt.Sprintf("%dC", c.temperature(i))), c.val(fmt.Sprintf("%d%%", c.util(10, i))), c.val("10.50W"))
+		}
+		fmt.Fprint(w, sep)
+	}
+	return nil
+}
+
+func (c Config) WriteSmiVersion(w io.Writer) {
+	c.writeBanner(w)
+	fmt.Fprintf(w, "lynxi-smi version: %s\n", Version)
+}
+
+// WriteLspci writes the output of lspci -d 1e9f:27c5.
+func (c Config) WriteLspci(w io.Writer) {
+	if c.Scenario == ScenarioMissing {
+		return
+	}
+	for b := 0; b < c.Boards; b++ {
+		for i := 0; i < c.Chips; i++ {
+			fmt.Fprintf(w, "%s Processing accelerators: Device %s:%s (rev 01)\n", c.PciAddress(b, i), VendorId, DeviceId)
+		}
+	}
+}
+
+// WriteDpkg writes the dpkg -l lines of the packages named in pattern, all of them when pattern is empty.
+func (c Config) WriteDpkg(w io.Writer, pattern ...string) {
+	packages := []struct {
+		name, version, description string
+	}{
+		{"lyndriver", Version, "Lynxi APU driver"},
+		{"lynsdk", SdkVersion, "Lynxi APU SDK"},
+	}
+	for _, p := range packages {
+		if len(pattern) > 0 && !containsAny(p.name, pattern) {
+			continue
+		}
+		fmt.Fprintf(w, "ii  %s  %s  amd64  %s\n", p.name, p.version, p.description)
+	}
+}
+
+func containsAny(name string, pattern []string) bool {
+	for _, p := range pattern {
+		if strings.Contains(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteSysfs writes the PCI device attributes and the driver version below root.
+func (c Config) WriteSysfs(root string) error {
+	if c.Scenario == ScenarioMissing {
+		return nil
+	}
+	moduleDir := filepath.Join(root, "module", "lyndriver")
+	if err := os.MkdirAll(moduleDir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(moduleDir, "version"), []byte(Version+"\n"), 0644); err != nil {
+		return err
+	}
+	for b := 0; b < c.Boards; b++ {
+		for i := 0; i < c.Chips; i++ {
+			path := filepath.Join(append([]string{"devices"}, c.PciPath(b, i)...)...)
+			dir := filepath.Join(root, path)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+			link := filepath.Join(root, "bus", "pci", "devices", "0000:"+c.PciAddress(b, i))
+			if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+				return err
+			}
+			_ = os.RemoveAll(link)
+			if err := os.Symlink(filepath.Join("..", "..", "..", path), link); err != nil {
+				return err
+			}
+			currentWidth := "8"
+			if i%2 == 1 {
+				currentWidth = "4"
+			}
+			attrs := map[string]string{
+				"vendor":             "0x" + VendorId,
+				"device":             "0x" + DeviceId,
+				"subsystem_vendor":   "0x" + VendorId,
+				"subsystem_device":   "0x" + SubDeviceId,
+				"max_link_speed":     "8.0 GT/s PCIe",
+				"current_link_speed": "8.0 GT/s PCIe",
+				"max_link_width":     "8",
+				"current_link_width": currentWidth,
+				"numa_node":          strconv.Itoa(c.NumaNode(b)),
+				"local_cpulist":      c.NumaCPUList(b),
+			}
+			for name, val := range attrs {
+				if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(val+"\n"), 0644); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
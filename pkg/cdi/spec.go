@@ -0,0 +1,123 @@
+// Package cdi generates the Container Device Interface spec of the chips, container runtimes like podman and
+// containerd inject the devices of the spec with --device lynxi.com/apu=0.
+package cdi
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"lynxi_smi_pro/pkg/exporter"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version        = "0.5.0"
+	Kind           = "lynxi.com/apu"
+	AllDevice      = "all"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	DefaultLibGlob = "liblyn*.so*"
+	// __VOID_DEVICES__ is the LYNXI_VISIBLE_DEVICES of the containers of the spec, the runtime wrapper does not inject
+	// the devices again.
+	__VOID_DEVICES__ = "void"
+)
+
+// DefaultLibDirs are the directories searched for the driver and SDK libraries.
+var DefaultLibDirs = []string{"/usr/lib", "/usr/lib64", "/usr/lib/x86_64-linux-gnu", "/usr/lib/aarch64-linux-gnu", "/usr/local/lynxi/sdk/sdk/lib"}
+
+var mountOptions = []string{"ro", "nosuid", "nodev", "bind"}
+
+type Spec struct {
+	Version        string         `json:"cdiVersion" yaml:"cdiVersion"`
+	Kind           string         `json:"kind" yaml:"kind"`
+	Devices        []Device       `json:"devices" yaml:"devices"`
+	ContainerEdits ContainerEdits `json:"containerEdits,omitempty" yaml:"containerEdits,omitempty"`
+}
+
+type Device struct {
+	Name           string         `json:"name" yaml:"name"`
+	ContainerEdits ContainerEdits `json:"containerEdits" yaml:"containerEdits"`
+}
+
+type ContainerEdits struct {
+	Env         []string     `json:"env,omitempty" yaml:"env,omitempty"`
+	DeviceNodes []DeviceNode `json:"deviceNodes,omitempty" yaml:"deviceNodes,omitempty"`
+	Mounts      []Mount      `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+}
+
+type DeviceNode struct {
+	Path        string `json:"path" yaml:"path"`
+	Permissions string `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+}
+
+type Mount struct {
+	HostPath      string   `json:"hostPath" yaml:"hostPath"`
+	ContainerPath string   `json:"containerPath" yaml:"containerPath"`
+	Options       []string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// FindLibraries returns the files matching DefaultLibGlob in dirs and the lynxi-smi command, they are mounted read-only
+// into the containers.
+func FindLibraries(dirs []string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, DefaultLibGlob))
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				paths = append(paths, m)
+			}
+		}
+	}
+	if smi, err := exec.LookPath(exporter.DefaultLynSmiCommand); err == nil {
+		paths = append(paths, smi)
+	}
+	return paths
+}
+
+// Generate returns the spec with a device per chip index, a device per chip UUID and the all device. The mounts of the
+// libraries and the env are common to every device.
+func Generate(chips []exporter.ChipDevice, libraries []string) Spec {
+	spec := Spec{Version: Version, Kind: Kind}
+	var all ContainerEdits
+	for _, c := range chips {
+		node := DeviceNode{Path: exporter.DevicePath(c.Index), Permissions: "rw"}
+		all.DeviceNodes = append(all.DeviceNodes, node)
+		spec.Devices = append(spec.Devices, Device{Name: strconv.Itoa(c.Index), ContainerEdits: ContainerEdits{DeviceNodes: []DeviceNode{node}}})
+		if c.Uuid != "" {
+			spec.Devices = append(spec.Devices, Device{Name: c.Uuid, ContainerEdits: ContainerEdits{DeviceNodes: []DeviceNode{node}}})
+		}
+	}
+	if len(chips) > 0 {
+		spec.Devices = append(spec.Devices, Device{Name: AllDevice, ContainerEdits: all})
+	}
+	spec.ContainerEdits.Env = []string{exporter.VisibleDevicesEnv + "=" + __VOID_DEVICES__}
+	for _, lib := range libraries {
+		spec.ContainerEdits.Mounts = append(spec.ContainerEdits.Mounts, Mount{HostPath: lib, ContainerPath: lib, Options: mountOptions})
+	}
+	return spec
+}
+
+// Marshal encodes the spec as JSON or YAML.
+func (s Spec) Marshal(format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(s, "", "  ")
+		return append(data, '\n'), err
+	case FormatYAML:
+		return yaml.Marshal(s)
+	}
+	return nil, fmt.Errorf("unknown CDI spec format %s, expected %s or %s", strconv.Quote(format), FormatJSON, FormatYAML)
+}
+
+// FormatOf returns the format of a spec file from its extension, YAML unless it is .json.
+func FormatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), "."+FormatJSON) {
+		return FormatJSON
+	}
+	return FormatYAML
+}
@@ -0,0 +1,50 @@
+package cdi
+
+import (
+	"encoding/json"
+	"lynxi_smi_pro/pkg/exporter"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	spec := Generate([]exporter.ChipDevice{{Index: 0, Uuid: "1e9f27c5-4c59-4e58-0000-000000000000"}, {Index: 1}}, []string{"/usr/lib/liblyn.so.1"})
+	var names []string
+	for _, d := range spec.Devices {
+		names = append(names, d.Name)
+	}
+	if want := []string{"0", "1e9f27c5-4c59-4e58-0000-000000000000", "1", AllDevice}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Generate devices = %v, want %v", names, want)
+	}
+	if got := spec.Devices[1].ContainerEdits.DeviceNodes[0].Path; got != "/dev/lynd0" {
+		t.Errorf("device of the UUID = %s, want /dev/lynd0", got)
+	}
+	if got := len(spec.Devices[3].ContainerEdits.DeviceNodes); got != 2 {
+		t.Errorf("all device has %d device nodes, want 2", got)
+	}
+	if got := spec.ContainerEdits.Mounts[0]; got.HostPath != "/usr/lib/liblyn.so.1" || got.ContainerPath != got.HostPath {
+		t.Errorf("library mount = %v", got)
+	}
+
+	data, err := spec.Marshal(FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Spec
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, spec) {
+		t.Errorf("JSON spec does not decode to the spec: %v\n%s", err, data)
+	}
+	data, err = spec.Marshal(FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"cdiVersion: 0.5.0\n", "kind: lynxi.com/apu\n", "- name: all\n", "- LYNXI_VISIBLE_DEVICES=void\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("YAML spec does not contain %q:\n%s", want, data)
+		}
+	}
+	if FormatOf("/etc/cdi/lynxi.json") != FormatJSON || FormatOf("/etc/cdi/lynxi.yaml") != FormatYAML {
+		t.Error("FormatOf does not follow the extension")
+	}
+}
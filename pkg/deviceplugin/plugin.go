@@ -0,0 +1,305 @@
+// Package deviceplugin registers the chips of the node with kubelet as the lynxi.com/apu extended resource, over the
+// device plugin API of Kubernetes.
+package deviceplugin
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"lynxi_smi_pro/pkg/exporter"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultResourceName   = "lynxi.com/apu"
+	DefaultSocketName     = "lynxi-apu.sock"
+	DefaultHealthInterval = 10 * time.Second
+	__DIAL_TIMEOUT__      = 5 * time.Second
+	__DEVICE_PERMS__      = "rw"
+	__KUBELET_SOCKET__    = "kubelet.sock"
+)
+
+// Options configures a Plugin, PluginDir holds the kubelet registration socket and the socket of the plugin. Query
+// returns the chips of the node, exporter.QueryBoardMetrics when nil.
+type Options struct {
+	PluginDir      string
+	ResourceName   string
+	SocketName     string
+	HealthInterval time.Duration
+	Query          func() ([]exporter.BoardMetrics, error)
+}
+
+// chipDevice is a chip advertised to kubelet, its ID is the UUID of the chip.
+type chipDevice struct {
+	device    pluginapi.Device
+	chipIndex int
+	reason    string
+}
+
+// Plugin serves the device plugin API on a unix socket of PluginDir and keeps the health of the chips up to date.
+type Plugin struct {
+	opts    Options
+	mu      sync.Mutex
+	devices map[string]chipDevice
+	update  chan struct{}
+	server  *grpc.Server
+}
+
+func New(opts Options) *Plugin {
+	if opts.PluginDir == "" {
+		opts.PluginDir = pluginapi.DevicePluginPath
+	}
+	if opts.ResourceName == "" {
+		opts.ResourceName = DefaultResourceName
+	}
+	if opts.SocketName == "" {
+		opts.SocketName = DefaultSocketName
+	}
+	if opts.HealthInterval <= 0 {
+		opts.HealthInterval = DefaultHealthInterval
+	}
+	if opts.Query == nil {
+		opts.Query = exporter.QueryBoardMetrics
+	}
+	return &Plugin{opts: opts, devices: make(map[string]chipDevice), update: make(chan struct{})}
+}
+
+func (p *Plugin) socketPath() string {
+	return filepath.Join(p.opts.PluginDir, p.opts.SocketName)
+}
+
+// Run serves the plugin and registers it with kubelet until ctx is done. kubelet removes the sockets of the plugins
+// when it restarts, the plugin then serves a new socket and registers again.
+func (p *Plugin) Run(ctx context.Context) error {
+	if err := p.refresh(); err != nil {
+		return err
+	}
+	for {
+		if err := p.serve(); err != nil {
+			return err
+		}
+		if err := p.register(ctx); err != nil {
+			p.stop()
+			return err
+		}
+		log.Infof("Registered %s with kubelet, socket %s", p.opts.ResourceName, p.socketPath())
+		restart := p.watch(ctx)
+		p.stop()
+		if !restart {
+			return nil
+		}
+		log.Infof("Socket %s removed, registering again", p.socketPath())
+	}
+}
+
+func (p *Plugin) serve() error {
+	if err := os.Remove(p.socketPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	listener, err := net.Listen("unix", p.socketPath())
+	if err != nil {
+		return err
+	}
+	p.server = grpc.NewServer()
+	pluginapi.RegisterDevicePluginServer(p.server, p)
+	go func() {
+		if err := p.server.Serve(listener); err != nil {
+			log.Errorf("device plugin server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (p *Plugin) stop() {
+	p.server.Stop()
+	os.Remove(p.socketPath())
+}
+
+func (p *Plugin) register(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, __DIAL_TIMEOUT__)
+	defer cancel()
+	conn, err := dialUnix(ctx, filepath.Join(p.opts.PluginDir, __KUBELET_SOCKET__))
+	if err != nil {
+		return fmt.Errorf("cannot connect to kubelet: %v", err)
+	}
+	defer conn.Close()
+	_, err = pluginapi.NewRegistrationClient(conn).Register(ctx, &pluginapi.RegisterRequest{
+		Version:      pluginapi.Version,
+		Endpoint:     p.opts.SocketName,
+		ResourceName: p.opts.ResourceName,
+		Options:      &pluginapi.DevicePluginOptions{},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot register with kubelet: %v", err)
+	}
+	return nil
+}
+
+func dialUnix(ctx context.Context, path string) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, path, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+		}))
+}
+
+// watch refreshes the health of the chips every HealthInterval until ctx is done, or until the socket of the plugin is
+// removed and the plugin has to register again.
+func (p *Plugin) watch(ctx context.Context) bool {
+	ticker := time.NewTicker(p.opts.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+		if _, err := os.Stat(p.socketPath()); err != nil {
+			return true
+		}
+		if err := p.refresh(); err != nil {
+			log.Warnf("cannot query the chips, reporting them as unhealthy: %v", err)
+		}
+	}
+}
+
+// refresh queries the chips and notifies ListAndWatch when a device or its health changed. Chips that are no longer
+// reported, or all of them when the query fails, stay advertised as unhealthy.
+func (p *Plugin) refresh() error {
+	boards, err := p.opts.Query()
+	devices := make(map[string]chipDevice)
+	for _, b := range boards {
+		for _, c := range b.Chips {
+			d := newChipDevice(c)
+			devices[d.device.ID] = d
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, old := range p.devices {
+		if _, ok := devices[id]; !ok {
+			old.device.Health, old.reason = pluginapi.Unhealthy, "chip not reported"
+			if err != nil {
+				old.reason = err.Error()
+			}
+			devices[id] = old
+		}
+	}
+	changed := len(devices) != len(p.devices)
+	for id, d := range devices {
+		old, ok := p.devices[id]
+		if ok && old.device.Health == d.device.Health {
+			continue
+		}
+		changed = true
+		if d.device.Health == pluginapi.Unhealthy {
+			log.Warnf("Device %s of chip%d is unhealthy: %s", id, d.chipIndex, d.reason)
+		} else if ok {
+			log.Infof("Device %s of chip%d is healthy again", id, d.chipIndex)
+		}
+	}
+	p.devices = devices
+	if changed {
+		close(p.update)
+		p.update = make(chan struct{})
+	}
+	return err
+}
+
+// newChipDevice advertises a chip by its UUID, by its PCI address when the UUID is not reported and by its index without
+// PCI address, the ids the runtime wrapper resolves. Uncorrected ECC errors and a temperature at the slowdown temperature
+// make the chip unhealthy.
+func newChipDevice(c exporter.ChipMetrics) chipDevice {
+	id := c.Uuid
+	if !c.HasUuid() {
+		id = c.Pci.BusId
+	}
+	if id == "" {
+		id = strconv.Itoa(c.ChipIndex)
+	}
+	d := chipDevice{device: pluginapi.Device{ID: id, Health: pluginapi.Healthy}, chipIndex: c.ChipIndex}
+	if c.Pci.NumaNode != nil && *c.Pci.NumaNode >= 0 {
+		d.device.Topology = &pluginapi.TopologyInfo{Nodes: []*pluginapi.NUMANode{{ID: int64(*c.Pci.NumaNode)}}}
+	}
+	switch headroom := c.TemperatureHeadroom(); {
+	case c.EccErrorsUncorrected != nil && *c.EccErrorsUncorrected > 0:
+		d.device.Health = pluginapi.Unhealthy
+		d.reason = fmt.Sprintf("%d uncorrected ECC errors", *c.EccErrorsUncorrected)
+	case headroom != nil && *headroom <= 0:
+		d.device.Health = pluginapi.Unhealthy
+		d.reason = fmt.Sprintf("temperature %g C reached the slowdown temperature %g C", *c.Temperature, *c.TemperatureSlowdown)
+	}
+	return d
+}
+
+// listDevices returns the advertised devices sorted by chip index and the channel closed on their next change.
+func (p *Plugin) listDevices() ([]*pluginapi.Device, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	devices := make([]chipDevice, 0, len(p.devices))
+	for _, d := range p.devices {
+		devices = append(devices, d)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].chipIndex < devices[j].chipIndex })
+	list := make([]*pluginapi.Device, len(devices))
+	for i := range devices {
+		list[i] = &devices[i].device
+	}
+	return list, p.update
+}
+
+func (p *Plugin) GetDevicePluginOptions(context.Context, *pluginapi.Empty) (*pluginapi.DevicePluginOptions, error) {
+	return &pluginapi.DevicePluginOptions{}, nil
+}
+
+// ListAndWatch sends the devices, then again on every change of a device or its health.
+func (p *Plugin) ListAndWatch(_ *pluginapi.Empty, stream pluginapi.DevicePlugin_ListAndWatchServer) error {
+	for {
+		devices, update := p.listDevices()
+		if err := stream.Send(&pluginapi.ListAndWatchResponse{Devices: devices}); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-update:
+		}
+	}
+}
+
+func (p *Plugin) GetPreferredAllocation(context.Context, *pluginapi.PreferredAllocationRequest) (*pluginapi.PreferredAllocationResponse, error) {
+	return &pluginapi.PreferredAllocationResponse{}, nil
+}
+
+// Allocate returns the device nodes of the requested chips and lists their IDs in LYNXI_VISIBLE_DEVICES.
+func (p *Plugin) Allocate(_ context.Context, req *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	resp := &pluginapi.AllocateResponse{}
+	for _, creq := range req.ContainerRequests {
+		cresp := &pluginapi.ContainerAllocateResponse{}
+		for _, id := range creq.DevicesIDs {
+			d, ok := p.devices[id]
+			if !ok {
+				return nil, fmt.Errorf("unknown device %s", strconv.Quote(id))
+			}
+			path := exporter.DevicePath(d.chipIndex)
+			cresp.Devices = append(cresp.Devices, &pluginapi.DeviceSpec{ContainerPath: path, HostPath: path, Permissions: __DEVICE_PERMS__})
+		}
+		cresp.Envs = map[string]string{exporter.VisibleDevicesEnv: strings.Join(creq.DevicesIDs, ",")}
+		resp.ContainerResponses = append(resp.ContainerResponses, cresp)
+	}
+	return resp, nil
+}
+
+func (p *Plugin) PreStartContainer(context.Context, *pluginapi.PreStartContainerRequest) (*pluginapi.PreStartContainerResponse, error) {
+	return &pluginapi.PreStartContainerResponse{}, nil
+}
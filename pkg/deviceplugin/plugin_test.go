@@ -0,0 +1,156 @@
+package deviceplugin
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"lynxi_smi_pro/pkg/exporter"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeKubelet serves the registration API of kubelet and records the requests.
+type fakeKubelet struct {
+	requests chan *pluginapi.RegisterRequest
+}
+
+func (k *fakeKubelet) Register(_ context.Context, req *pluginapi.RegisterRequest) (*pluginapi.Empty, error) {
+	k.requests <- req
+	return &pluginapi.Empty{}, nil
+}
+
+func startFakeKubelet(t *testing.T, dir string) *fakeKubelet {
+	listener, err := net.Listen("unix", filepath.Join(dir, __KUBELET_SOCKET__))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := &fakeKubelet{requests: make(chan *pluginapi.RegisterRequest, 1)}
+	server := grpc.NewServer()
+	pluginapi.RegisterRegistrationServer(server, k)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return k
+}
+
+// fakeChips is the query of the plugin, set changes the reported chips.
+type fakeChips struct {
+	mu     sync.Mutex
+	boards []exporter.BoardMetrics
+}
+
+func (f *fakeChips) query() ([]exporter.BoardMetrics, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.boards, nil
+}
+
+func (f *fakeChips) set(boards []exporter.BoardMetrics) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.boards = boards
+}
+
+func testBoards(temperature exporter.Celsius) []exporter.BoardMetrics {
+	slowdown := exporter.Celsius(95)
+	numaNode := 1
+	var zero uint64
+	return []exporter.BoardMetrics{{Chips: []exporter.ChipMetrics{
+		{ChipIndex: 0, Uuid: "1e9f27c5-4c59-4e58-0000-000000000000", Temperature: &temperature, TemperatureSlowdown: &slowdown,
+			EccErrorsUncorrected: &zero, Pci: exporter.ChipPci{NumaNode: &numaNode}},
+		{ChipIndex: 1, Uuid: "N/A", Pci: exporter.ChipPci{BusId: "0000:04:00.0"}},
+	}}}
+}
+
+func TestPlugin(t *testing.T) {
+	dir := t.TempDir()
+	kubelet := startFakeKubelet(t, dir)
+	chips := &fakeChips{boards: testBoards(45)}
+	plugin := New(Options{PluginDir: dir, HealthInterval: 10 * time.Millisecond, Query: chips.query})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- plugin.Run(ctx) }()
+
+	var req *pluginapi.RegisterRequest
+	select {
+	case req = <-kubelet.requests:
+	case err := <-done:
+		t.Fatalf("Run = %v before registering", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("plugin did not register")
+	}
+	if req.Version != pluginapi.Version || req.ResourceName != DefaultResourceName || req.Endpoint != DefaultSocketName {
+		t.Errorf("RegisterRequest = %v", req)
+	}
+
+	conn, err := dialUnix(ctx, filepath.Join(dir, req.Endpoint))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pluginapi.NewDevicePluginClient(conn)
+	stream, err := client.ListAndWatch(ctx, &pluginapi.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Devices) != 2 || resp.Devices[0].ID != "1e9f27c5-4c59-4e58-0000-000000000000" || resp.Devices[1].ID != "0000:04:00.0" {
+		t.Fatalf("ListAndWatch devices = %v", resp.Devices)
+	}
+	if resp.Devices[0].Health != pluginapi.Healthy || resp.Devices[0].Topology.Nodes[0].ID != 1 {
+		t.Errorf("ListAndWatch device = %v", resp.Devices[0])
+	}
+
+	chips.set(testBoards(97))
+	if resp, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Devices[0].Health != pluginapi.Unhealthy || resp.Devices[1].Health != pluginapi.Healthy {
+		t.Errorf("ListAndWatch after slowdown = %v", resp.Devices)
+	}
+
+	alloc, err := client.Allocate(ctx, &pluginapi.AllocateRequest{ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+		{DevicesIDs: []string{"0000:04:00.0", "1e9f27c5-4c59-4e58-0000-000000000000"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := alloc.ContainerResponses[0]
+	if len(c.Devices) != 2 || c.Devices[0].HostPath != "/dev/lynd1" || c.Devices[1].ContainerPath != "/dev/lynd0" {
+		t.Errorf("Allocate devices = %v", c.Devices)
+	}
+	if got := c.Envs[exporter.VisibleDevicesEnv]; got != "0000:04:00.0,1e9f27c5-4c59-4e58-0000-000000000000" {
+		t.Errorf("Allocate %s = %q", exporter.VisibleDevicesEnv, got)
+	}
+	if _, err := client.Allocate(ctx, &pluginapi.AllocateRequest{ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+		{DevicesIDs: []string{"chip9"}},
+	}}); err == nil {
+		t.Error("Allocate of an unknown device succeeded")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run = %v", err)
+	}
+}
+
+func TestNewChipDeviceId(t *testing.T) {
+	cases := []struct {
+		chip exporter.ChipMetrics
+		want string
+	}{
+		{exporter.ChipMetrics{ChipIndex: 2, Uuid: "1e9f27c5-4c59-4e58-0000-000000000002", Pci: exporter.ChipPci{BusId: "0000:05:00.0"}}, "1e9f27c5-4c59-4e58-0000-000000000002"},
+		{exporter.ChipMetrics{ChipIndex: 2, Uuid: "N/A", Pci: exporter.ChipPci{BusId: "0000:05:00.0"}}, "0000:05:00.0"},
+		{exporter.ChipMetrics{ChipIndex: 2, Uuid: "N/A"}, "2"},
+	}
+	for _, c := range cases {
+		if got := newChipDevice(c.chip).device.ID; got != c.want {
+			t.Errorf("newChipDevice(%v) ID = %s, want %s", c.chip, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const __RANGE_SEP__ = "-"
+
+// Affinity is the CPUs and memory nodes local to a set of chips.
+type Affinity struct {
+	CPUs      []int `json:"cpus"`
+	NumaNodes []int `json:"numa_nodes"`
+}
+
+// ParseCPUList parses the list format of sysfs and cgroup cpusets, e.g. 0-7,32-39.
+func ParseCPUList(list string) ([]int, error) {
+	var cpus []int
+	for _, part := range strings.Split(strings.TrimSpace(list), __COMMA_SEP__) {
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, __RANGE_SEP__, 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %s", strconv.Quote(list))
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU list %s", strconv.Quote(list))
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// FormatCPUList formats ids in the list format, consecutive ids are joined to ranges.
+func FormatCPUList(ids []int) string {
+	sorted := uniqueSorted(ids)
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		} else {
+			parts = append(parts, strconv.Itoa(sorted[i])+__RANGE_SEP__+strconv.Itoa(sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, __COMMA_SEP__)
+}
+
+func uniqueSorted(ids []int) []int {
+	seen := make(map[int]bool)
+	var sorted []int
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			sorted = append(sorted, id)
+		}
+	}
+	sort.Ints(sorted)
+	return sorted
+}
+
+// ChipAffinity returns the union of the local CPUs and NUMA nodes of the chips.
+func ChipAffinity(chips []ChipTopology) (Affinity, error) {
+	var a Affinity
+	if len(chips) == 0 {
+		return a, errors.New("no chip selected")
+	}
+	for _, c := range chips {
+		if c.CPUList == "" {
+			return a, fmt.Errorf("CPU affinity of %s%d is not available", ChipIdPrefix, c.ChipIndex)
+		}
+		cpus, err := ParseCPUList(c.CPUList)
+		if err != nil {
+			return a, err
+		}
+		a.CPUs = append(a.CPUs, cpus...)
+		if c.NumaNode != nil {
+			a.NumaNodes = append(a.NumaNodes, *c.NumaNode)
+		}
+	}
+	a.CPUs = uniqueSorted(a.CPUs)
+	a.NumaNodes = uniqueSorted(a.NumaNodes)
+	return a, nil
+}
+
+func (a Affinity) CPUList() string {
+	return FormatCPUList(a.CPUs)
+}
+
+// MemList returns the NUMA nodes in the list format, it is empty when the chips have no NUMA node.
+func (a Affinity) MemList() string {
+	return FormatCPUList(a.NumaNodes)
+}
@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCPUList(t *testing.T) {
+	cases := []struct {
+		list string
+		cpus []int
+		norm string
+	}{
+		{"0-3,8,10-11\n", []int{0, 1, 2, 3, 8, 10, 11}, "0-3,8,10-11"},
+		{"5", []int{5}, "5"},
+		{"4-5,0-1,5", []int{4, 5, 0, 1, 5}, "0-1,4-5"},
+		{"", nil, ""},
+	}
+	for _, c := range cases {
+		cpus, err := ParseCPUList(c.list)
+		if err != nil || !reflect.DeepEqual(cpus, c.cpus) {
+			t.Errorf("ParseCPUList(%q) = %v, %v, want %v", c.list, cpus, err, c.cpus)
+		}
+		if got := FormatCPUList(cpus); got != c.norm {
+			t.Errorf("FormatCPUList(%v) = %q, want %q", cpus, got, c.norm)
+		}
+	}
+	for _, list := range []string{"a", "3-1", "1-b"} {
+		if _, err := ParseCPUList(list); err == nil {
+			t.Errorf("ParseCPUList(%q) did not fail", list)
+		}
+	}
+}
+
+func TestChipAffinity(t *testing.T) {
+	node0, node1 := 0, 1
+	a, err := ChipAffinity([]ChipTopology{
+		{ChipIndex: 0, NumaNode: &node0, CPUList: "0-7,32-39"},
+		{ChipIndex: 1, NumaNode: &node0, CPUList: "0-7,32-39"},
+		{ChipIndex: 4, NumaNode: &node1, CPUList: "8-15"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.CPUList() != "0-15,32-39" || a.MemList() != "0-1" {
+		t.Errorf("ChipAffinity() = %s %s, want 0-15,32-39 0-1", a.CPUList(), a.MemList())
+	}
+	if _, err := ChipAffinity([]ChipTopology{{ChipIndex: 2}}); err == nil {
+		t.Error("ChipAffinity() of a chip without CPU list did not fail")
+	}
+}
@@ -0,0 +1,266 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CheckState is the state of a Nagios plugin, its value is the exit code of the plugin.
+type CheckState int
+
+const (
+	CheckOK CheckState = iota
+	CheckWarning
+	CheckCritical
+	CheckUnknown
+)
+
+func (s CheckState) String() string {
+	switch s {
+	case CheckOK:
+		return "OK"
+	case CheckWarning:
+		return "WARNING"
+	case CheckCritical:
+		return "CRITICAL"
+	}
+	return "UNKNOWN"
+}
+
+const (
+	__CHECK_SERVICE__      = "APU"
+	__RANGE_INSIDE__       = "@"
+	__RANGE_NEG_INFINITY__ = "~"
+	__PERCENT_UNIT__       = "[%]"
+)
+
+// Range is a threshold range of the Nagios plugin guidelines: 10 alerts outside of 0..10, 10: below 10, ~:10 above 10,
+// 10:20 outside of 10..20 and @10:20 inside of 10..20.
+type Range struct {
+	Start  float64
+	End    float64
+	Inside bool
+	raw    string
+}
+
+func ParseRange(raw string) (Range, error) {
+	r := Range{Start: 0, End: math.Inf(1), raw: raw}
+	s := raw
+	if strings.HasPrefix(s, __RANGE_INSIDE__) {
+		r.Inside = true
+		s = strings.TrimPrefix(s, __RANGE_INSIDE__)
+	}
+	bounds := strings.SplitN(s, __COLON_SEP__, 2)
+	var err error
+	if len(bounds) == 1 {
+		if r.End, err = strconv.ParseFloat(bounds[0], 64); err != nil {
+			return r, fmt.Errorf("invalid threshold range %s", strconv.Quote(raw))
+		}
+		return r, nil
+	}
+	switch bounds[0] {
+	case __RANGE_NEG_INFINITY__:
+		r.Start = math.Inf(-1)
+	case "":
+	default:
+		if r.Start, err = strconv.ParseFloat(bounds[0], 64); err != nil {
+			return r, fmt.Errorf("invalid threshold range %s", strconv.Quote(raw))
+		}
+	}
+	if bounds[1] != "" {
+		if r.End, err = strconv.ParseFloat(bounds[1], 64); err != nil {
+			return r, fmt.Errorf("invalid threshold range %s", strconv.Quote(raw))
+		}
+	}
+	if r.Start > r.End {
+		return r, fmt.Errorf("invalid threshold range %s, start is greater than end", strconv.Quote(raw))
+	}
+	return r, nil
+}
+
+// Alert reports whether v raises an alert for the range.
+func (r Range) Alert(v float64) bool {
+	inside := v >= r.Start && v <= r.End
+	return inside == r.Inside
+}
+
+func (r Range) String() string {
+	return r.raw
+}
+
+// CheckThreshold is the warning and critical range of the query fields matching Field, a pattern like
+// temperature.current.chip*.
+type CheckThreshold struct {
+	Field    string
+	Warning  *Range
+	Critical *Range
+}
+
+// ParseCheckThresholds parses the FIELD=RANGE values of --warning and --critical, FIELD is a field pattern.
+func ParseCheckThresholds(warning []string, critical []string) ([]CheckThreshold, error) {
+	byField := make(map[string]*CheckThreshold)
+	var fields []string
+	add := func(ranges []string, critical bool) error {
+		for _, v := range ranges {
+			field, raw := splitFieldRange(v)
+			if field == "" {
+				return fmt.Errorf("invalid threshold %s, expected FIELD=RANGE", strconv.Quote(v))
+			}
+			if _, err := path.Match(field, ""); err != nil {
+				return fmt.Errorf("invalid field pattern %s", strconv.Quote(field))
+			}
+			r, err := ParseRange(raw)
+			if err != nil {
+				return err
+			}
+			t, ok := byField[field]
+			if !ok {
+				t = &CheckThreshold{Field: field}
+				byField[field] = t
+				fields = append(fields, field)
+			}
+			if critical {
+				t.Critical = &r
+			} else {
+				t.Warning = &r
+			}
+		}
+		return nil
+	}
+	if err := add(warning, false); err != nil {
+		return nil, err
+	}
+	if err := add(critical, true); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("no threshold given, use --warning or --critical")
+	}
+	sort.Strings(fields)
+	thresholds := make([]CheckThreshold, len(fields))
+	for i, f := range fields {
+		thresholds[i] = *byField[f]
+	}
+	return thresholds, nil
+}
+
+// PerfData is a performance data value of the plugin output, labeled with the board index and the field.
+type PerfData struct {
+	Label    string
+	Value    float64
+	Unit     string
+	Warning  *Range
+	Critical *Range
+}
+
+func (p PerfData) String() string {
+	s := fmt.Sprintf("%s=%s%s;", p.Label, strconv.FormatFloat(p.Value, 'f', -1, 64), p.Unit)
+	if p.Warning != nil {
+		s += p.Warning.String()
+	}
+	s += ";"
+	if p.Critical != nil {
+		s += p.Critical.String()
+	}
+	return strings.TrimRight(s, ";")
+}
+
+// CheckResult is the outcome of a check, Problems lists the values outside of their range with the worst state first.
+type CheckResult struct {
+	State    CheckState
+	Problems []string
+	PerfData []PerfData
+}
+
+// String formats the result as the status line of a Nagios plugin, the performance data follows the |.
+func (c CheckResult) String() string {
+	summary := strings.Join(c.Problems, ", ")
+	if summary == "" {
+		summary = fmt.Sprintf("%d values within thresholds", len(c.PerfData))
+	}
+	perfData := make([]string, len(c.PerfData))
+	for i, p := range c.PerfData {
+		perfData[i] = p.String()
+	}
+	line := fmt.Sprintf("%s %s - %s", __CHECK_SERVICE__, c.State, summary)
+	if len(perfData) > 0 {
+		line += " | " + strings.Join(perfData, " ")
+	}
+	return line
+}
+
+func splitFieldRange(v string) (string, string) {
+	i := strings.Index(v, "=")
+	if i < 0 {
+		return "", ""
+	}
+	return strings.TrimSpace(v[:i]), strings.TrimSpace(v[i+1:])
+}
+
+// CheckUnknownResult is the result of a check that could not query the values.
+func CheckUnknownResult(err error) CheckResult {
+	return CheckResult{State: CheckUnknown, Problems: []string{err.Error()}}
+}
+
+// RunCheck evaluates the thresholds over the query fields of the boards. Values reported as N/A and the per chip fields of
+// the chips that are not selected are skipped, a threshold without any value makes the check unknown.
+func RunCheck(boards []BoardBaseInfo, thresholds []CheckThreshold, selection *DeviceSelection) CheckResult {
+	if len(boards) == 0 {
+		return CheckUnknownResult(errors.New("no APU found"))
+	}
+	var result CheckResult
+	var warnings, criticals, unknowns []string
+	for _, t := range thresholds {
+		known, matched := false, false
+		for _, b := range boards {
+			chipCount, _ := strconv.Atoi(b.ChipCount)
+			var fields []string
+			for _, f := range buildQFields(chipCount) {
+				if ok, _ := path.Match(t.Field, string(f)); ok {
+					fields = append(fields, string(f))
+				}
+			}
+			known = known || len(fields) > 0
+			for i, val := range QuerySelectedFieldValues(b, fields, selection) {
+				v, ok := parseFloatVal(val)
+				if !ok {
+					continue
+				}
+				matched = true
+				label := fmt.Sprintf("board%s.%s", b.BoardIndex, fields[i])
+				p := PerfData{Label: label, Value: v, Warning: t.Warning, Critical: t.Critical}
+				if FieldUnit(fields[i]) == __PERCENT_UNIT__ {
+					p.Unit = "%"
+				}
+				result.PerfData = append(result.PerfData, p)
+				switch {
+				case t.Critical != nil && t.Critical.Alert(v):
+					criticals = append(criticals, fmt.Sprintf("%s=%s (critical %s)", label, val, t.Critical))
+				case t.Warning != nil && t.Warning.Alert(v):
+					warnings = append(warnings, fmt.Sprintf("%s=%s (warning %s)", label, val, t.Warning))
+				}
+			}
+		}
+		switch {
+		case !known:
+			unknowns = append(unknowns, fmt.Sprintf("no field matches %s", t.Field))
+		case !matched:
+			unknowns = append(unknowns, fmt.Sprintf("no numeric value for %s", t.Field))
+		}
+	}
+	result.Problems = append(append(append(result.Problems, criticals...), unknowns...), warnings...)
+	switch {
+	case len(criticals) > 0:
+		result.State = CheckCritical
+	case len(unknowns) > 0:
+		result.State = CheckUnknown
+	case len(warnings) > 0:
+		result.State = CheckWarning
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		raw    string
+		alerts []float64
+		passes []float64
+	}{
+		{"10", []float64{-1, 10.5}, []float64{0, 10}},
+		{"10:", []float64{9.9}, []float64{10, 1000}},
+		{"~:10", []float64{11}, []float64{-1000, 10}},
+		{"10:20", []float64{9, 21}, []float64{10, 20}},
+		{"@10:20", []float64{10, 20}, []float64{9, 21}},
+	}
+	for _, c := range cases {
+		r, err := ParseRange(c.raw)
+		if err != nil {
+			t.Errorf("ParseRange(%q): %v", c.raw, err)
+			continue
+		}
+		for _, v := range c.alerts {
+			if !r.Alert(v) {
+				t.Errorf("range %s does not alert on %g", c.raw, v)
+			}
+		}
+		for _, v := range c.passes {
+			if r.Alert(v) {
+				t.Errorf("range %s alerts on %g", c.raw, v)
+			}
+		}
+	}
+	for _, raw := range []string{"", "x", "20:10", "1:x"} {
+		if _, err := ParseRange(raw); err == nil {
+			t.Errorf("ParseRange(%q) accepted an invalid range", raw)
+		}
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	var boards []BoardBaseInfo
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "multi_board"), func() {
+		boards, err = QueryBoards()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		warning  []string
+		critical []string
+		want     CheckState
+		line     string
+	}{
+		{[]string{"power.draw=~:60"}, nil, CheckOK,
+			"APU OK - 2 values within thresholds | board0.power.draw=31.5;~:60 board1.power.draw=31.5;~:60"},
+		{[]string{"ecc.errors.corrected.total=0"}, []string{"ecc.errors.uncorrected.total=0"}, CheckWarning,
+			"APU WARNING - board1.ecc.errors.corrected.total=6 (warning 0) | board0.ecc.errors.corrected.total=0;0 board1.ecc.errors.corrected.total=6;0 board0.ecc.errors.uncorrected.total=0;;0 board1.ecc.errors.uncorrected.total=0;;0"},
+		{nil, []string{"utilization.apu.chip*=@0:100"}, CheckCritical, ""},
+		{nil, []string{"name=1"}, CheckUnknown, "APU UNKNOWN - no numeric value for name"},
+		{nil, []string{"temperature.chip*=1"}, CheckUnknown, "APU UNKNOWN - no field matches temperature.chip*"},
+	}
+	for _, c := range cases {
+		thresholds, err := ParseCheckThresholds(c.warning, c.critical)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := RunCheck(boards, thresholds, nil)
+		if got.State != c.want || c.line != "" && got.String() != c.line {
+			t.Errorf("RunCheck(%v, %v) = %v\n%s\nwant %v\n%s", c.warning, c.critical, got.State, got, c.want, c.line)
+		}
+	}
+}
+
+func TestRunCheckSelection(t *testing.T) {
+	var boards []BoardBaseInfo
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "multi_board"), func() {
+		boards, err = QueryBoards()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	selection, err := SelectDevices(NewBoardMetricsList(boards), []string{"chip1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	thresholds, err := ParseCheckThresholds(nil, []string{"temperature.current.chip*=80"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := RunCheck(boards[:1], thresholds, selection)
+	if len(got.PerfData) != 1 || got.PerfData[0].Label != "board0.temperature.current.chip1" {
+		t.Errorf("RunCheck with chip1 selected = %s", got)
+	}
+}
+
+func TestParseCheckThresholdsInvalid(t *testing.T) {
+	for _, v := range []string{"foo", "temperature.current.chip*=", "=80", "[=80", "power.draw=abc"} {
+		if _, err := ParseCheckThresholds([]string{v}, nil); err == nil {
+			t.Errorf("ParseCheckThresholds(%q) accepted an invalid threshold", v)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package exporter
+
+import (
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// ChipDevice is a chip of the system with its device node, Uuid is empty when lynxi-smi does not report it.
+type ChipDevice struct {
+	Index int
+	Uuid  string
+	BusId string
+}
+
+// QueryChipDevices enumerates the chips from the PCI device list in the order of their index, their UUIDs from
+// lynxi-smi. The chips are still listed without UUID when lynxi-smi fails.
+func QueryChipDevices() ([]ChipDevice, error) {
+	pciDeviceList, err := QueryChipList()
+	if err != nil {
+		return nil, err
+	}
+	uuids := make(map[int]string)
+	if boards, err := QueryBoardMetrics(); err != nil {
+		log.Warnf("cannot query the chip UUIDs: %v", err)
+	} else {
+		for _, b := range boards {
+			for _, c := range b.Chips {
+				if c.Uuid != "" && !strings.Contains(c.Uuid, __N_A_STR__) {
+					uuids[c.ChipIndex] = c.Uuid
+				}
+			}
+		}
+	}
+	chips := make([]ChipDevice, len(pciDeviceList))
+	for i, line := range pciDeviceList {
+		chips[i] = ChipDevice{Index: i, Uuid: uuids[i], BusId: __PCI_DOMAIN__ + __COLON_SEP__ + strings.Split(line, " ")[0]}
+	}
+	return chips, nil
+}
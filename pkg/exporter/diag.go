@@ -0,0 +1,304 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Verdict is the result of a diagnostic check, ordered from the best to the worst.
+type Verdict int
+
+const (
+	VerdictPass Verdict = iota
+	VerdictWarn
+	VerdictFail
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case VerdictPass:
+		return "PASS"
+	case VerdictWarn:
+		return "WARN"
+	}
+	return "FAIL"
+}
+
+func (v Verdict) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+const (
+	DiagQuick  = "quick"
+	DiagMedium = "medium"
+	DiagLong   = "long"
+)
+
+var DiagLevels = []string{DiagQuick, DiagMedium, DiagLong}
+
+const (
+	DefaultDiagDuration = 30 * time.Second
+	// DiagTempMargin is the distance to the slowdown temperature below which a chip is reported as warning.
+	DiagTempMargin   Celsius = 10
+	DiagInputVoltage Volts   = 12
+	// DiagVoltageTolerance is the accepted deviation of the board input voltage, as a fraction of DiagInputVoltage.
+	DiagVoltageTolerance  = 0.1
+	__DIAG_SYSTEM__       = "system"
+	__DIAG_LONG_SAMPLES__ = 10
+)
+
+// DiagResult is the verdict of a check on a target, the system, a board or a chip.
+type DiagResult struct {
+	Check   string  `json:"check"`
+	Target  string  `json:"target"`
+	Verdict Verdict `json:"verdict"`
+	Details string  `json:"details"`
+}
+
+// DiagOptions configures RunDiag, Duration is the sampling time of the long level.
+type DiagOptions struct {
+	Level    string
+	Duration time.Duration
+}
+
+// WorstVerdict returns the worst verdict of the results, VerdictPass without results.
+func WorstVerdict(results []DiagResult) Verdict {
+	worst := VerdictPass
+	for _, r := range results {
+		if r.Verdict > worst {
+			worst = r.Verdict
+		}
+	}
+	return worst
+}
+
+func boardTarget(b BoardMetrics) string {
+	return fmt.Sprintf("board%d", b.BoardIndex)
+}
+
+func chipTarget(c ChipMetrics) string {
+	return fmt.Sprintf("%s%d", ChipIdPrefix, c.ChipIndex)
+}
+
+// RunDiag runs the checks of the level: quick checks the driver, lynxi-smi and the chip count, medium adds the PCIe
+// links, ECC errors, temperatures, fan and voltages, long samples the boards for the duration and checks they are stable.
+func RunDiag(opts DiagOptions) ([]DiagResult, error) {
+	level := -1
+	for i, l := range DiagLevels {
+		if l == opts.Level {
+			level = i
+		}
+	}
+	if level < 0 {
+		return nil, fmt.Errorf("unknown diagnostic level %q, expected one of %s", opts.Level, strings.Join(DiagLevels, ", "))
+	}
+	results := []DiagResult{diagDriver()}
+	boards, err := QueryBoardMetrics()
+	if err != nil {
+		return append(results, DiagResult{"lynxi-smi", __DIAG_SYSTEM__, VerdictFail, err.Error()}), nil
+	}
+	results = append(results, DiagResult{"lynxi-smi", __DIAG_SYSTEM__, VerdictPass, fmt.Sprintf("%d boards reported", len(boards))})
+	results = append(results, diagChipCount(boards))
+	if level < 1 {
+		return results, nil
+	}
+	for _, b := range boards {
+		results = append(results, diagFan(b), diagInputVoltage(b))
+		for _, c := range b.Chips {
+			results = append(results, diagPcieLink(c), diagEcc(c), diagTemperature(c), diagChipVoltage(c))
+		}
+	}
+	if level < 2 {
+		return results, nil
+	}
+	return append(results, diagStability(boards, opts.Duration)...), nil
+}
+
+func diagDriver() DiagResult {
+	version := getVersion(Driver)
+	if version == "" || version == __UNKNOWN_STR__ {
+		return DiagResult{"driver", __DIAG_SYSTEM__, VerdictFail, "driver version not found"}
+	}
+	return DiagResult{"driver", __DIAG_SYSTEM__, VerdictPass, version}
+}
+
+func diagChipCount(boards []BoardMetrics) DiagResult {
+	smiCount := 0
+	for _, b := range boards {
+		smiCount += len(b.Chips)
+	}
+	pciCount, err := QueryChipTotalNum()
+	switch {
+	case err != nil:
+		return DiagResult{"chip count", __DIAG_SYSTEM__, VerdictFail, err.Error()}
+	case pciCount == 0:
+		return DiagResult{"chip count", __DIAG_SYSTEM__, VerdictFail, "no APU found on the PCI bus"}
+	case pciCount != smiCount:
+		return DiagResult{"chip count", __DIAG_SYSTEM__, VerdictFail, fmt.Sprintf("%d chips on the PCI bus, %d reported by lynxi-smi", pciCount, smiCount)}
+	}
+	return DiagResult{"chip count", __DIAG_SYSTEM__, VerdictPass, fmt.Sprintf("%d chips", pciCount)}
+}
+
+func diagPcieLink(c ChipMetrics) DiagResult {
+	r := DiagResult{Check: "pcie link", Target: chipTarget(c)}
+	p := c.Pci
+	if p.LinkSpeedCurrent == nil || p.LinkSpeedMax == nil || p.LinkWidthCurrent == nil || p.LinkWidthMax == nil {
+		r.Verdict, r.Details = VerdictWarn, "link speed or width not reported"
+		return r
+	}
+	r.Details = fmt.Sprintf("%g GT/s x%d, max %g GT/s x%d", *p.LinkSpeedCurrent, *p.LinkWidthCurrent, *p.LinkSpeedMax, *p.LinkWidthMax)
+	if *p.LinkSpeedCurrent < *p.LinkSpeedMax || *p.LinkWidthCurrent < *p.LinkWidthMax {
+		r.Verdict, r.Details = VerdictWarn, "degraded, "+r.Details
+	}
+	return r
+}
+
+func diagEcc(c ChipMetrics) DiagResult {
+	r := DiagResult{Check: "ecc", Target: chipTarget(c)}
+	if c.EccErrorsCorrected == nil || c.EccErrorsUncorrected == nil {
+		r.Verdict, r.Details = VerdictWarn, "ECC error counts not reported"
+		return r
+	}
+	r.Details = fmt.Sprintf("%d corrected, %d uncorrected", *c.EccErrorsCorrected, *c.EccErrorsUncorrected)
+	switch {
+	case *c.EccErrorsUncorrected > 0:
+		r.Verdict = VerdictFail
+	case *c.EccErrorsCorrected > 0:
+		r.Verdict = VerdictWarn
+	}
+	return r
+}
+
+func diagTemperature(c ChipMetrics) DiagResult {
+	r := DiagResult{Check: "temperature", Target: chipTarget(c)}
+	if c.Temperature == nil {
+		r.Verdict, r.Details = VerdictWarn, "temperature not reported"
+		return r
+	}
+	r.Details = fmt.Sprintf("%g C", *c.Temperature)
+	if c.TemperatureSlowdown == nil {
+		return r
+	}
+	r.Details += fmt.Sprintf(", slowdown at %g C", *c.TemperatureSlowdown)
+	switch {
+	case *c.Temperature >= *c.TemperatureSlowdown:
+		r.Verdict = VerdictFail
+	case *c.Temperature >= *c.TemperatureSlowdown-DiagTempMargin:
+		r.Verdict = VerdictWarn
+	}
+	return r
+}
+
+// diagFan passes boards without fan, their fan speed is reported as N/A.
+func diagFan(b BoardMetrics) DiagResult {
+	r := DiagResult{Check: "fan", Target: boardTarget(b)}
+	switch {
+	case b.FanSpeed == nil:
+		r.Details = "no fan reported"
+	case *b.FanSpeed <= 0:
+		r.Verdict, r.Details = VerdictFail, "fan stopped"
+	default:
+		r.Details = fmt.Sprintf("%g %%", *b.FanSpeed)
+	}
+	return r
+}
+
+func diagInputVoltage(b BoardMetrics) DiagResult {
+	r := DiagResult{Check: "voltage", Target: boardTarget(b)}
+	if b.VoltageInput == nil {
+		r.Verdict, r.Details = VerdictWarn, "input voltage not reported"
+		return r
+	}
+	r.Details = fmt.Sprintf("input %.2f V", *b.VoltageInput)
+	deviation := *b.VoltageInput - DiagInputVoltage
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	if float64(deviation) > DiagVoltageTolerance*float64(DiagInputVoltage) {
+		r.Verdict = VerdictFail
+		r.Details += fmt.Sprintf(", expected %g V ±%g%%", DiagInputVoltage, DiagVoltageTolerance*100)
+	}
+	return r
+}
+
+func diagChipVoltage(c ChipMetrics) DiagResult {
+	r := DiagResult{Check: "voltage", Target: chipTarget(c)}
+	if c.Voltage == nil || *c.Voltage <= 0 {
+		r.Verdict, r.Details = VerdictWarn, "chip voltage not reported"
+		return r
+	}
+	r.Details = fmt.Sprintf("%.2f V", *c.Voltage)
+	return r
+}
+
+// diagStability samples the boards during duration, lynxi-smi has to answer every time and the ECC error counts of the
+// chips must not increase.
+func diagStability(first []BoardMetrics, duration time.Duration) []DiagResult {
+	if duration <= 0 {
+		duration = DefaultDiagDuration
+	}
+	interval := duration / __DIAG_LONG_SAMPLES__
+	failed := 0
+	var lastErr error
+	last := first
+	increased := make(map[int]string)
+	for i := 0; i < __DIAG_LONG_SAMPLES__; i++ {
+		time.Sleep(interval)
+		boards, err := QueryBoardMetrics()
+		if err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		for _, c := range eccIncreases(last, boards) {
+			increased[c.ChipIndex] = chipTarget(c)
+		}
+		last = boards
+	}
+	results := []DiagResult{{"sampling", __DIAG_SYSTEM__, VerdictPass,
+		fmt.Sprintf("%d samples in %s", __DIAG_LONG_SAMPLES__, duration)}}
+	if failed > 0 {
+		results[0].Verdict = VerdictFail
+		results[0].Details = fmt.Sprintf("%d of %d samples failed: %v", failed, __DIAG_LONG_SAMPLES__, lastErr)
+	}
+	for _, b := range last {
+		for _, c := range b.Chips {
+			r := DiagResult{"ecc stability", chipTarget(c), VerdictPass, "ECC error counts stable"}
+			if _, ok := increased[c.ChipIndex]; ok {
+				r.Verdict, r.Details = VerdictFail, "ECC error counts increased while sampling"
+			}
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+func eccIncreases(before []BoardMetrics, after []BoardMetrics) []ChipMetrics {
+	counts := make(map[int]uint64)
+	for _, b := range before {
+		for _, c := range b.Chips {
+			counts[c.ChipIndex] = eccErrorCount(c)
+		}
+	}
+	var increased []ChipMetrics
+	for _, b := range after {
+		for _, c := range b.Chips {
+			if count, ok := counts[c.ChipIndex]; ok && eccErrorCount(c) > count {
+				increased = append(increased, c)
+			}
+		}
+	}
+	return increased
+}
+
+func eccErrorCount(c ChipMetrics) uint64 {
+	var count uint64
+	if c.EccErrorsCorrected != nil {
+		count += *c.EccErrorsCorrected
+	}
+	if c.EccErrorsUncorrected != nil {
+		count += *c.EccErrorsUncorrected
+	}
+	return count
+}
@@ -0,0 +1,58 @@
+package exporter
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRunDiag(t *testing.T) {
+	var results []DiagResult
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "multi_board"), func() {
+		results, err = RunDiag(DiagOptions{Level: DiagMedium})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verdicts := make(map[string]Verdict)
+	for _, r := range results {
+		verdicts[r.Check+" "+r.Target] = r.Verdict
+	}
+	for _, key := range []string{"driver system", "lynxi-smi system", "chip count system", "ecc chip0", "temperature chip0"} {
+		if v, ok := verdicts[key]; !ok || v != VerdictPass {
+			t.Errorf("%s = %v, %v, want PASS", key, v, ok)
+		}
+	}
+	if v := verdicts["ecc chip5"]; v != VerdictWarn {
+		t.Errorf("ecc chip5 = %v, want WARN for corrected errors", v)
+	}
+	if _, err := RunDiag(DiagOptions{Level: "short"}); err == nil {
+		t.Error("RunDiag accepted an unknown level")
+	}
+}
+
+func TestDiagTemperature(t *testing.T) {
+	slowdown := Celsius(95)
+	cases := []struct {
+		temp *Celsius
+		want Verdict
+	}{
+		{nil, VerdictWarn},
+		{celsius(45), VerdictPass},
+		{celsius(85), VerdictWarn},
+		{celsius(95), VerdictFail},
+	}
+	for _, c := range cases {
+		got := diagTemperature(ChipMetrics{Temperature: c.temp, TemperatureSlowdown: &slowdown})
+		if got.Verdict != c.want {
+			t.Errorf("diagTemperature(%v) = %v, want %v", c.temp, got, c.want)
+		}
+	}
+	if got := WorstVerdict([]DiagResult{{Verdict: VerdictWarn}, {Verdict: VerdictPass}}); got != VerdictWarn {
+		t.Errorf("WorstVerdict = %v, want WARN", got)
+	}
+}
+
+func celsius(v Celsius) *Celsius {
+	return &v
+}
@@ -0,0 +1,176 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// EventType names a change of state between two samples, conditions have an event when they start and when they clear.
+type EventType string
+
+const (
+	EventChipAppeared        EventType = "chip_appeared"
+	EventChipDisappeared     EventType = "chip_disappeared"
+	EventEccErrors           EventType = "ecc_errors_increased"
+	EventTempSlowdown        EventType = "temperature_slowdown_reached"
+	EventTempSlowdownCleared EventType = "temperature_slowdown_cleared"
+	EventPcieDowntrained     EventType = "pcie_link_downtrained"
+	EventPcieRestored        EventType = "pcie_link_restored"
+	EventPowerCap            EventType = "power_cap_reached"
+	EventPowerCapCleared     EventType = "power_cap_cleared"
+	EventFanNotAvailable     EventType = "fan_not_available"
+	EventFanAvailable        EventType = "fan_available"
+	EventQueryFailed         EventType = "query_failed"
+)
+
+// Event is a change of state of a board or a chip, ChipIndex is nil for board events.
+type Event struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Type         EventType `json:"type"`
+	BoardIndex   *int      `json:"board_index,omitempty"`
+	SerialNumber string    `json:"serial_number,omitempty"`
+	ChipIndex    *int      `json:"chip_index,omitempty"`
+	Uuid         string    `json:"uuid,omitempty"`
+	Message      string    `json:"message"`
+}
+
+// QueryFailedEvent reports a sample that could not be taken, the next sample is compared to the last successful one.
+func QueryFailedEvent(timestamp time.Time, err error) Event {
+	return Event{Timestamp: timestamp, Type: EventQueryFailed, Message: err.Error()}
+}
+
+type chipState struct {
+	board BoardMetrics
+	chip  ChipMetrics
+}
+
+// chipKey identifies a chip across samples by its UUID, or by its index when the UUID is not reported.
+func chipKey(c ChipMetrics) string {
+	if c.Uuid != "" && c.Uuid != __N_A_STR__ {
+		return c.Uuid
+	}
+	return ChipIdPrefix + strconv.Itoa(c.ChipIndex)
+}
+
+func chipStates(boards []BoardMetrics) (map[string]chipState, []string) {
+	states := make(map[string]chipState)
+	var keys []string
+	for _, b := range boards {
+		for _, c := range b.Chips {
+			key := chipKey(c)
+			states[key] = chipState{b, c}
+			keys = append(keys, key)
+		}
+	}
+	return states, keys
+}
+
+func newBoardEvent(timestamp time.Time, typ EventType, b BoardMetrics, message string) Event {
+	boardIndex := b.BoardIndex
+	return Event{Timestamp: timestamp, Type: typ, BoardIndex: &boardIndex, SerialNumber: b.SerialNumber, Message: message}
+}
+
+func newChipEvent(timestamp time.Time, typ EventType, s chipState, message string) Event {
+	e := newBoardEvent(timestamp, typ, s.board, message)
+	chipIndex := s.chip.ChipIndex
+	e.ChipIndex = &chipIndex
+	e.Uuid = s.chip.Uuid
+	return e
+}
+
+func slowdownReached(c ChipMetrics) bool {
+	return c.Temperature != nil && c.TemperatureSlowdown != nil && *c.Temperature >= *c.TemperatureSlowdown
+}
+
+func pcieDowntrained(c ChipMetrics) bool {
+	p := c.Pci
+	speed := p.LinkSpeedCurrent != nil && p.LinkSpeedMax != nil && *p.LinkSpeedCurrent < *p.LinkSpeedMax
+	width := p.LinkWidthCurrent != nil && p.LinkWidthMax != nil && *p.LinkWidthCurrent < *p.LinkWidthMax
+	return speed || width
+}
+
+func pcieLinkStr(p ChipPci) string {
+	if p.LinkSpeedCurrent == nil || p.LinkWidthCurrent == nil || p.LinkSpeedMax == nil || p.LinkWidthMax == nil {
+		return __N_A_STR__
+	}
+	return fmt.Sprintf("%g GT/s x%d, max %g GT/s x%d", *p.LinkSpeedCurrent, *p.LinkWidthCurrent, *p.LinkSpeedMax, *p.LinkWidthMax)
+}
+
+func powerCapReached(b BoardMetrics) bool {
+	return b.PowerDraw != nil && b.PowerLimit != nil && *b.PowerDraw >= *b.PowerLimit
+}
+
+// DiffEvents returns the events between the samples prev and cur taken at timestamp. A nil prev is the first sample:
+// every chip appears and the conditions already present are reported, ECC errors are only reported as increments of a
+// chip present in both samples.
+func DiffEvents(prev []BoardMetrics, cur []BoardMetrics, timestamp time.Time) []Event {
+	var events []Event
+	prevChips, prevKeys := chipStates(prev)
+	curChips, curKeys := chipStates(cur)
+	prevBoards := make(map[int]BoardMetrics)
+	for _, b := range prev {
+		prevBoards[b.BoardIndex] = b
+	}
+	for _, key := range prevKeys {
+		if _, ok := curChips[key]; !ok {
+			events = append(events, newChipEvent(timestamp, EventChipDisappeared, prevChips[key], "chip is no longer reported"))
+		}
+	}
+	for _, b := range cur {
+		old, seen := prevBoards[b.BoardIndex]
+		if capped := powerCapReached(b); capped != (seen && powerCapReached(old)) {
+			if capped {
+				events = append(events, newBoardEvent(timestamp, EventPowerCap, b,
+					fmt.Sprintf("power draw %.2f W reached the power limit %.2f W", *b.PowerDraw, *b.PowerLimit)))
+			} else if seen {
+				events = append(events, newBoardEvent(timestamp, EventPowerCapCleared, b, "power draw is below the power limit"))
+			}
+		}
+		noFan := b.FanSpeed == nil
+		if !seen && noFan || seen && noFan != (old.FanSpeed == nil) {
+			if noFan {
+				events = append(events, newBoardEvent(timestamp, EventFanNotAvailable, b, "fan speed is reported as N/A"))
+			} else {
+				events = append(events, newBoardEvent(timestamp, EventFanAvailable, b, fmt.Sprintf("fan speed is %g %%", *b.FanSpeed)))
+			}
+		}
+	}
+	for _, key := range curKeys {
+		s := curChips[key]
+		old, seen := prevChips[key]
+		if !seen {
+			events = append(events, newChipEvent(timestamp, EventChipAppeared, s, "chip is reported"))
+		} else {
+			corrected := counterDelta(old.chip.EccErrorsCorrected, s.chip.EccErrorsCorrected)
+			uncorrected := counterDelta(old.chip.EccErrorsUncorrected, s.chip.EccErrorsUncorrected)
+			if corrected > 0 || uncorrected > 0 {
+				events = append(events, newChipEvent(timestamp, EventEccErrors, s,
+					fmt.Sprintf("%d corrected and %d uncorrected ECC errors since the last sample", corrected, uncorrected)))
+			}
+		}
+		if hot := slowdownReached(s.chip); hot != (seen && slowdownReached(old.chip)) {
+			if hot {
+				events = append(events, newChipEvent(timestamp, EventTempSlowdown, s,
+					fmt.Sprintf("temperature %g C reached the slowdown temperature %g C", *s.chip.Temperature, *s.chip.TemperatureSlowdown)))
+			} else if seen {
+				events = append(events, newChipEvent(timestamp, EventTempSlowdownCleared, s, "temperature is below the slowdown temperature"))
+			}
+		}
+		if down := pcieDowntrained(s.chip); down != (seen && pcieDowntrained(old.chip)) {
+			if down {
+				events = append(events, newChipEvent(timestamp, EventPcieDowntrained, s, "PCIe link "+pcieLinkStr(s.chip.Pci)))
+			} else if seen {
+				events = append(events, newChipEvent(timestamp, EventPcieRestored, s, "PCIe link "+pcieLinkStr(s.chip.Pci)))
+			}
+		}
+	}
+	return events
+}
+
+func counterDelta(prev *uint64, cur *uint64) uint64 {
+	if prev == nil || cur == nil || *cur < *prev {
+		return 0
+	}
+	return *cur - *prev
+}
@@ -0,0 +1,59 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+)
+
+func eventTypes(events []Event) []EventType {
+	types := make([]EventType, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestDiffEvents(t *testing.T) {
+	counter := func(v uint64) *uint64 { return &v }
+	percent := func(v Percent) *Percent { return &v }
+	chip := func(index int, uuid string, temp Celsius, corrected uint64) ChipMetrics {
+		return ChipMetrics{ChipIndex: index, Uuid: uuid, Temperature: celsius(temp), TemperatureSlowdown: celsius(95),
+			EccErrorsCorrected: counter(corrected), EccErrorsUncorrected: counter(0)}
+	}
+	board := func(fan *Percent, chips ...ChipMetrics) []BoardMetrics {
+		return []BoardMetrics{{SerialNumber: "2203A0012", FanSpeed: fan, Chips: chips}}
+	}
+	now := time.Unix(1700000000, 0)
+	cases := []struct {
+		name string
+		prev []BoardMetrics
+		cur  []BoardMetrics
+		want []EventType
+	}{
+		{"first sample", nil, board(nil, chip(0, "a", 45, 0), chip(1, "b", 97, 0)),
+			[]EventType{EventFanNotAvailable, EventChipAppeared, EventChipAppeared, EventTempSlowdown}},
+		{"unchanged", board(percent(40), chip(0, "a", 97, 1)), board(percent(40), chip(0, "a", 97, 1)), nil},
+		{"ecc increment", board(percent(40), chip(0, "a", 45, 1)), board(percent(40), chip(0, "a", 45, 3)),
+			[]EventType{EventEccErrors}},
+		{"slowdown cleared", board(percent(40), chip(0, "a", 97, 0)), board(percent(40), chip(0, "a", 60, 0)),
+			[]EventType{EventTempSlowdownCleared}},
+		{"chip replaced", board(percent(40), chip(0, "a", 45, 5)), board(percent(40), chip(0, "b", 45, 0)),
+			[]EventType{EventChipDisappeared, EventChipAppeared}},
+		{"fan lost", board(percent(40), chip(0, "a", 45, 0)), board(nil, chip(0, "a", 45, 0)),
+			[]EventType{EventFanNotAvailable}},
+	}
+	for _, c := range cases {
+		events := DiffEvents(c.prev, c.cur, now)
+		got := eventTypes(events)
+		if len(got) != len(c.want) {
+			t.Errorf("%s: DiffEvents = %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] || !events[i].Timestamp.Equal(now) {
+				t.Errorf("%s: DiffEvents = %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
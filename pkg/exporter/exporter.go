@@ -1,7 +1,8 @@
 package exporter
 
 import (
-	"fmt"
+	"bufio"
+	"errors"
 	log "github.com/sirupsen/logrus"
 	"io"
 	"strconv"
@@ -9,45 +10,112 @@ import (
 	"time"
 )
 
-func QueryAPUHelpInfo() {
-	fmt.Print("List of valid properties to query for the switch query-apu:\n\n")
-	for _, v := range fallbackQField {
-		fmt.Println(strconv.Quote(string(v)))
-		fmt.Println(fallbackQFieldComment[v])
-		fmt.Println()
+func QueryFieldHelp() []FieldHelp {
+	return QueryFieldHelpByChipCount(detectChipCount())
+}
+
+func QueryFieldHelpByChipCount(chipCount int) []FieldHelp {
+	qFields := buildQFields(chipCount)
+	fieldHelpList := make([]FieldHelp, len(qFields))
+	for i, v := range qFields {
+		fieldHelpList[i] = FieldHelp{
+			Name:        string(v),
+			Description: getQFieldComment(v),
+			Unit:        getQFieldUnit(v),
+		}
 	}
+	return fieldHelpList
 }
 
-func QueryLynSmiInfo() {
+func QuerySmiInfo() (string, error) {
+	var sb strings.Builder
 	fn := func(line string) {
 		line = replaceNAInfo(line)
 		line = replaceProductNameToSmiVersionName(line)
 		line = replaceDriverVersionInfo(line)
-		fmt.Print(line)
+		sb.WriteString(line)
+	}
+	err := RunLynSMICmdAndReadStrings(fn)
+	return sb.String(), err
+}
+
+func QuerySnapshot() (*Snapshot, error) {
+	timestamp := time.Now()
+	boardBaseInfoList, err := QueryBoards()
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Timestamp: timestamp, Boards: boardBaseInfoList}, nil
+}
+
+func QueryBoards() ([]BoardBaseInfo, error) {
+	return queryBoardBaseInfoList()
+}
+
+func ParseQueryFields(qFieldsRaw string) ([]string, error) {
+	qFields, err := verifyAndCheckQueryFields(qFieldsRaw)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, len(qFields))
+	for i, f := range qFields {
+		fields[i] = string(f)
 	}
-	RunLynSMICmdAndReadStrings(fn)
+	return fields, nil
 }
 
-func QueryLynAPUsInfo(qFieldsRaw string) {
+func QueryFieldValues(info BoardBaseInfo, fields []string) []string {
+	boardBaseInfoStrMap := apusInfoToFlatMap(structToMap(info))
+	board := NewBoardMetrics(info)
+	addTemperatureHeadroomFields(boardBaseInfoStrMap, board)
+	addThrottleReasonFields(boardBaseInfoStrMap, board)
+	vals := make([]string, len(fields))
+	for i, f := range fields {
+		vals[i] = string(getAPUInfoByBoardMapInfo(boardBaseInfoStrMap, qField(f)))
+	}
+	return vals
+}
+
+// QuerySelectedFieldValues is QueryFieldValues with the per chip fields of the chips that are not selected reported as N/A.
+func QuerySelectedFieldValues(info BoardBaseInfo, fields []string, selection *DeviceSelection) []string {
+	vals := QueryFieldValues(info, fields)
+	if selection == nil {
+		return vals
+	}
+	chips := NewBoardMetrics(info).Chips
+	for i, f := range fields {
+		t, position, exists := lookupQFieldTemplate(qField(f))
+		if exists && t.perChip && position < len(chips) && !selection.HasChip(chips[position].ChipIndex) {
+			vals[i] = __N_A_STR__
+		}
+	}
+	return vals
+}
+
+func FieldUnit(field string) string {
+	return getQFieldUnit(qField(field))
+}
+
+func queryBoardBaseInfoList() ([]BoardBaseInfo, error) {
 	var (
 		boardBaseInfo     BoardBaseInfo
 		boardBaseInfoList []BoardBaseInfo
 	)
-	qFields, err := verifyAndCheckQueryFields(qFieldsRaw)
+	r, c, err := runLynSMIDetailCommand()
 	if err != nil {
-		return
+		return nil, err
 	}
-	r, cmd := runLynSMIDetailCommand()
+	defer closeAndLog(c)
+	sampleTime := formatTimeStamp(time.Now())
 	line, err := r.ReadString(__LINE_FEED_SEP__)
+	err = removeDebugInfo(&line, r, err)
 	ch := make(chan []string)
 	go QueryLynPciInfo(ch)
 	pciInfoStrList := <-ch
 	fn := func(info string) {
 		switch {
 		case strings.Contains(info, __BOARD_STR__):
-			now := time.Now()
-			timestamp := now.Unix()
-			boardBaseInfo.TimeStamp = strconv.FormatInt(timestamp, 10)
+			boardBaseInfo.TimeStamp = sampleTime
 			boardBaseInfo.BoardIndex = getBoardInfoVal(info)
 		case strings.Contains(info, __PRODUCT_NAME_STR__):
 			boardBaseInfo.ProductName = getBoardInfoVal(info)
@@ -56,7 +124,7 @@ func QueryLynAPUsInfo(qFieldsRaw string) {
 		case strings.Contains(info, __PRODUCT_NUMBER_STR__):
 			boardBaseInfo.ProductNumber = getBoardInfoVal(info)
 		case strings.Contains(info, __DRIVER_STR__):
-			boardBaseInfo.DriverVersion = strings.Split(getVersion(Driver), __V_SEP__)[1]
+			boardBaseInfo.DriverVersion = strings.TrimPrefix(getVersion(Driver), VersionShortStr)
 		case strings.Contains(info, __FIRMWARE_VERSION_STR__):
 			boardBaseInfo.FirmwareVersion = getBoardInfoVal(info)
 		case strings.Contains(info, __SERIAL_NUMBER_STR__):
@@ -178,14 +246,20 @@ func QueryLynAPUsInfo(qFieldsRaw string) {
 		case strings.Contains(info, __TEMPERATURE_STR__):
 			count, _ := strconv.Atoi(boardBaseInfo.ChipCount)
 			tempList := make([]string, count)
+			tempSlowdownList := make([]string, count)
+			tempShutdownList := make([]string, count)
 			for i := 0; i < count; i++ {
 				line, _ := r.ReadString(__LINE_FEED_SEP__)
 				line, _ = r.ReadString(__LINE_FEED_SEP__)
 				tempList[i] = getBoardInfoVal(line)
 				line, _ = r.ReadString(__LINE_FEED_SEP__)
+				tempSlowdownList[i] = getBoardInfoVal(line)
 				line, _ = r.ReadString(__LINE_FEED_SEP__)
+				tempShutdownList[i] = getBoardInfoVal(line)
 			}
 			boardBaseInfo.TempUtilList = tempList
+			boardBaseInfo.TempSlowdownList = tempSlowdownList
+			boardBaseInfo.TempShutdownList = tempShutdownList
 		case !strings.Contains(info, __BOARD_VOLTAGE_STR__) && strings.Contains(info, __VOLTAGE_STR__):
 			line, _ := r.ReadString(__LINE_FEED_SEP__)
 			count, _ := strconv.Atoi(boardBaseInfo.ChipCount)
@@ -277,38 +351,25 @@ func QueryLynAPUsInfo(qFieldsRaw string) {
 		line, err = r.ReadString(__LINE_FEED_SEP__)
 	}
 	if err != io.EOF {
-		fmt.Println(err)
-		return
-	}
-	err = cmd.Wait()
-	if err != nil {
-		log.Debugln(err)
+		return nil, err
 	}
 	log.Debugf("Board Info Number: %d", len(boardBaseInfoList))
-	printAPUsInfoTitle(qFields)
-	for _, v := range boardBaseInfoList {
-		mapData := structToMap(v)
-		boardBaseInfoStrMap := apusInfoToFlatMap(mapData)
-		for i, qField := range qFields {
-			val := getAPUInfoByBoardMapInfo(boardBaseInfoStrMap, qField)
-			if len(qFields)-1 != i {
-				fmt.Print(val + __COMMA_SEP__ + __SPCAE_SEP__)
-			} else {
-				fmt.Print(val)
-			}
-		}
-		fmt.Println()
-	}
+	return boardBaseInfoList, nil
 }
 
-func ListAPUs() {
+func ListBoards() ([]BoardSummary, error) {
+	var boardSummaryList []BoardSummary
 	var boardIndex int = 0
 	var chipCount int = 0
 	var boardSN string
 	var boardPN string
-	r, cmd := runLynSMIDetailCommand()
+	r, c, err := runLynSMIDetailCommand()
+	if err != nil {
+		return nil, err
+	}
+	defer closeAndLog(c)
 	line, err := r.ReadString(__LINE_FEED_SEP__)
-	removeDebugInfo(&line, r, err)
+	err = removeDebugInfo(&line, r, err)
 	for err == nil {
 		line = removeAndReplaceBaseInfo(line)
 		boardIndexStr, errCode := getBoardIndex(line)
@@ -328,58 +389,62 @@ func ListAPUs() {
 				boardSN = sn
 			}
 			if strings.Contains(line, __UTILIZATION_STR__) {
-				fmt.Println(__APU_STR__ + __SPCAE_SEP__ + strconv.Itoa(boardIndex) + __COLON_SEP__ + boardPN + "  (" + "SN: " + boardSN + __COMMA_SEP__ + " ChipCount: " + strings.TrimSpace(strconv.Itoa(chipCount)) + ")")
+				boardSummaryList = append(boardSummaryList, BoardSummary{
+					BoardIndex:   boardIndex,
+					ProductName:  boardPN,
+					SerialNumber: boardSN,
+					ChipCount:    chipCount,
+				})
 			}
 		}
 		line, err = r.ReadString(__LINE_FEED_SEP__)
 	}
 	if err != io.EOF {
-		fmt.Println(err)
-		return
-	}
-	err = cmd.Wait()
-	if err != nil {
-		log.Debugln(err)
-		return
+		return nil, err
 	}
+	return boardSummaryList, nil
 }
 
-func QueryLynSmiDetailInfoByChipIDAndBoardId(boardId *int, chipId *int) {
-	r, cmd := runLynSMICommandByChipIDAndBoardId(boardId, chipId)
-	getLynSmiDetailInfo(r, cmd)
-}
-
-func QueryLynSmiDetailInfoByBoardId(boardId *int) {
-	r, cmd := runLynSMICommandByBoardId(boardId)
-	getLynSmiDetailInfo(r, cmd)
-}
-
-func QueryLynSmiDetailInfo() {
-	r, cmd := runLynSMIDetailCommand()
-	getLynSmiDetailInfo(r, cmd)
+// QueryDetailInfo returns the lynxi-smi -q report, boardId and chipId narrow it down when not nil.
+func QueryDetailInfo(boardId *int, chipId *int) (string, error) {
+	var sb strings.Builder
+	var err error
+	var r *bufio.Reader
+	var c io.Closer
+	switch {
+	case boardId != nil && chipId != nil:
+		r, c, err = runLynSMICommandByChipIDAndBoardId(boardId, chipId)
+	case boardId != nil:
+		r, c, err = runLynSMICommandByBoardId(boardId)
+	case chipId != nil:
+		return "", errors.New("board index is requested")
+	default:
+		r, c, err = runLynSMIDetailCommand()
+	}
+	if err != nil {
+		return "", err
+	}
+	err = writeLynSmiDetailInfo(&sb, r, c)
+	return sb.String(), err
 }
 
 func QueryLynPciInfo(ch chan<- []string) {
 	var pciInfoStrList []string
-	fn := func(line string) {
+	pciDeviceList, err := currentSource.PciDevices()
+	if err != nil {
+		log.Debugln(err)
+	}
+	for _, line := range pciDeviceList {
 		pciInfoStrList = append(pciInfoStrList, string(strings.Split(line, " ")[0]))
 	}
-	RunShellCmdAndArgsAndReadString(fn, DefaultPCICommand, LynChipDeviceIDAndVendorIDCmdParam...)
 	ch <- pciInfoStrList
 }
 
-func QueryLynChipTotalNum() {
-	var chip_count int = 0
-	fn := func(line string) {
-		chip_count++
-	}
-	RunShellCmdAndArgsAndReadString(fn, DefaultPCICommand, LynChipDeviceIDAndVendorIDCmdParam...)
-	fmt.Printf("ChipTotalNumbyPci: %s\n", strconv.Itoa(chip_count))
+func QueryChipTotalNum() (int, error) {
+	pciDeviceList, err := currentSource.PciDevices()
+	return len(pciDeviceList), err
 }
 
-func QueryLynChipList() {
-	fn := func(line string) {
-		fmt.Print(line)
-	}
-	RunShellCmdAndArgsAndReadString(fn, DefaultPCICommand, LynChipDeviceIDAndVendorIDCmdParam...)
+func QueryChipList() ([]string, error) {
+	return currentSource.PciDevices()
 }
@@ -0,0 +1,1171 @@
+package exporter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// qField stands for query field - the field name before the query
+type qField string
+
+// rField stands for returned field - the field name as returned by the lynxi-smi
+type rField string
+type Version int64
+type PciInfo int64
+
+const (
+	__COLON_SEP__     = ":"
+	__LINE_FEED_STR__ = "\n"
+	__LINE_FEED_SEP__ = '\n'
+	__PER_SEP__       = "%"
+	__V_SEP__         = "V"
+	__C_SEP__         = "C"
+	__SPCAE_SEP__     = " "
+	__W_SEP__         = "W"
+	__DOT_SEP__       = "."
+	__COMMA_SEP__     = ","
+)
+
+const (
+	SDK Version = iota
+	Driver
+	SMI
+)
+
+const (
+	DefaultLynSmiCommand        = "lynxi-smi"
+	DefaultFindLynDriverCommand = "dpkg"
+	DefaultPCICommand           = "lspci"
+	LynDriverStr                = "lyndriver"
+	LynSdkStr                   = "lynsdk"
+	VersionShortStr             = "V"
+)
+
+const (
+	VendorId PciInfo = iota
+	DeviceId
+	SubVendorId
+	SubDeviceId
+	BusNum
+	Device
+	Function
+	MaxSpeed
+	MaxWidth
+	CurrentSpeed
+	CurrentWidth
+	NumaNodeId
+	NumaCPUList
+)
+
+// qFieldTemplate describes a query field, per chip fields are expanded to <name>0..<name>N for the detected chip count.
+type qFieldTemplate struct {
+	name    qField
+	perChip bool
+	unit    string
+	comment string
+}
+
+const defaultChipCount = 3
+
+var (
+	versionCacheLock sync.Mutex
+	versionCache     = make(map[Version]string)
+	chipCountLock    sync.Mutex
+	chipCountCache   int
+)
+
+var (
+	qFieldTemplates = []qFieldTemplate{
+		{__TIMESTAMP_KEY__, false, "", "The timestamp of when the query was made in seconds since the epoch, with millisecond precision."},
+		{__BOARD_INDEX_KEY__, false, "", "Zero based index of the APU board. Can change at each boot."},
+		{__PRODUCT_NAME_KEY__, false, "", "the product name of the APU board."},
+		{__PRODUCT_NUMBER_KEY__, false, "", "the product number of the APU board."},
+		{__DRIVER_VERSION_KEY__, false, "", "The version of the installed LYNXI display driver. This is an alphanumeric string."},
+		{__FIRMWARE_VERSION_KEY__, false, "", "The version of the installed LYNXI display firmware driver."},
+		{__SERIAL_NUMBER_KEY__, false, "", "This number matches the serial number physically printed on each board. It is a globally unique immutable alphanumeric value."},
+		{__CHIP_COUNT_KEY__, false, "", "The number of LYNXI APUs in the system."},
+		{__CHIP_ID_CHIP_KEY__, true, "", "This value is the globally unique immutable alphanumeric identifier of the APU%d"},
+		{__UUID_CHIP_KEY__, true, "", "This value is the globally unique immutable alphanumeric identifier of the APU%d. It does not correspond to any physical label on the board."},
+		{__CHIP_INDEX_CHIP_KEY__, true, "", "Zero based index of the APU%d. Can change at each boot."},
+		{__UTILIZATION_APU_TOTAL_KEY__, false, "[%]", "Percent of time over the past sample period during which global (device) apu was being read or written."},
+		{__UTILIZATION_APU_CHIP_KEY__, true, "[%]", "Percent of time over the past sample period during which global (APU%d) apu was being read or written."},
+		{__UTILIZATION_CPU_TOTAL_KEY__, false, "[%]", "Percent of time over the past sample period during which global (device) cpu was being read or written."},
+		{__UTILIZATION_CPU_CHIP_KEY__, true, "[%]", "Percent of time over the past sample period during which global (APU%d) cpu was being read or written."},
+		{__UTILIZATION_VIC_TOTAL_KEY__, false, "[%]", "Percent of time over the past sample period during which global (device) vic was being read or written."},
+		{__UTILIZATION_VIC_CHIP_KEY__, true, "[%]", "Percent of time over the past sample period during which global (APU%d) vic was being read or written."},
+		{__UTILIZATION_MEMORY_TOTAL_KEY__, false, "[%]", "Percent of time over the past sample period during which global (device) memory was being read or written."},
+		{__UTILIZATION_MEMORY_CHIP_KEY__, true, "[%]", "Percent of time over the past sample period during which global (APU%d) memory was being read or written."},
+		{__UTILIZATION_IPE_FPS_TOTAL_KEY__, false, "", "Percent of time over the past sample period during which global (device) ipe was being read or written."},
+		{__UTILIZATION_IPE_FPS_CHIP_KEY__, true, "", "Percent of time over the past sample period during which global (APU%d) ipe was being read or written."},
+		{__PCI_VENDOR_ID_CHIP_KEY__, true, "", "pci.vendor_id.chip%d, in hex."},
+		{__PCI_DEVICE_ID_CHIP_KEY__, true, "", "pci.device_id.chip%d, in hex."},
+		{__PCI_SUB_VENDOR_ID_CHIP_KEY__, true, "", "pci.sub_vendor_id.chip%d, in hex."},
+		{__PCI_SUB_DEVICE_ID_CHIP_KEY__, true, "", "pci.sub_device_id.chip%d, in hex."},
+		{__PCI_BUS_CHIP_KEY__, true, "", "pci.bus.chip%d, in hex."},
+		{__PCI_DEVICE_CHIP_KEY__, true, "", "pci.device.chip%d, in hex."},
+		{__PCI_SUB_FUNCTION_CHIP_KEY__, true, "", "pci.function.chip%d, in hex."},
+		{__PCI_NUMA_NODE_ID_CHIP_KEY__, true, "", "pci.numa.node_id.chip%d."},
+		{__PCI_NUMA_CPU_CHIP_KEY__, true, "", "pci.numa.cpu.chip%d."},
+		{__PCIE_LINK_SPEED_MAX_CHIP_KEY__, true, "", "The maximum PCI-E link width possible with this APU and system configuration. For example, if the APU supports a higher PCIe generation than the system supports then this reports the system PCIe generation."},
+		{__PCIE_LINK_SPEED_CURRENT_CHIP_KEY__, true, "", "The current PCI-E link width. These may be reduced when the APU is not in use."},
+		{__PCIE_LINK_GEN_MAX_CHIP_KEY__, true, "", "The maximum PCI-E link generation possible with this APU and system configuration. For example, if the APU supports a higher PCIe generation than the system supports then this reports the system PCIe generation."},
+		{__PCIE_LINK_GEN_CURRENT_CHIP_KEY__, true, "", "The current PCI-E link generation. These may be reduced when the APU is not in use."},
+		{__FAN_SPEED_KEY__, false, "[%]", "Fan speed, in %."},
+		{__TEMPERATURE_CURRENT_CHIP_KEY__, true, "", "Core APU%d temperature. in degrees C."},
+		{__TEMPERATURE_SLOWDOWN_CHIP_KEY__, true, "", "The BIU temperature at which APU%d slows down, in degrees C."},
+		{__TEMPERATURE_SHUTDOWN_CHIP_KEY__, true, "", "The BIU temperature at which APU%d shuts down, in degrees C."},
+		{__TEMPERATURE_HEADROOM_CHIP_KEY__, true, "", "Degrees C left before APU%d reaches its slowdown temperature."},
+		{__VOLTAGE_CURRENT_CHIP_KEY__, true, "", "Current APU%d Voltage. in voltage V."},
+		{__VOLTAGE_BOARD_INPUT_KEY__, false, "", "Voltage board input."},
+		{__CLOCKS_CURRENT_APU_CHIP_KEY__, true, "[MHz]", "Current apu frequency of APU%d clock."},
+		{__CLOCKS_CURRENT_CPU_CHIP_KEY__, true, "[MHz]", "Current cpu frequency of APU%d clock."},
+		{__CLOCKS_CURRENT_MEMORY_CHIP_KEY__, true, "[MHz]", "Current memory frequency of APU%d clock."},
+		{__CLOCKS_MAX_APU_CHIP_KEY__, true, "[MHz]", "Current max apu frequency of APU%d clock."},
+		{__CLOCKS_MAX_CPU_CHIP_KEY__, true, "[MHz]", "Current max cpu frequency of APU%d clock."},
+		{__CLOCKS_MAX_MEMORY_CHIP_KEY__, true, "[MHz]", "Current max memory frequency of APU%d clock."},
+		{__CLOCKS_THROTTLE_ACTIVE_CHIP_KEY__, true, "", "Active when a clock of APU%d is below its maximum, Not Active otherwise."},
+		{__CLOCKS_THROTTLE_THERMAL_CHIP_KEY__, true, "", "The clocks of APU%d are reduced because it reached its BIU slowdown temperature."},
+		{__CLOCKS_THROTTLE_POWER_CAP_CHIP_KEY__, true, "", "The clocks of APU%d are reduced because the board draws its power limit."},
+		{__CLOCKS_THROTTLE_IDLE_CHIP_KEY__, true, "", "The clocks of APU%d are reduced because it is idle."},
+		{__CLOCKS_THROTTLE_UNKNOWN_CHIP_KEY__, true, "", "The clocks of APU%d are reduced for another reason."},
+		{__POWER_DRAW__, false, "[W]", "The last measured power draw for the entire board, in watts. Only available if power management is supported. This reading is accurate to within +/- 5 watts."},
+		{__POWER_LIMIT__, false, "[W]", "The software power limit in watts. Set by software like nvidia-smi. On Kepler devices Power Limit can be adjusted using [-pl | --power-limit=] switches."},
+		{__ECC_MODE_CURRENT_CHIP_KEY__, true, "", "Current Ecc mode APU%d."},
+		{__ECC_ERRORS_CORRECTED_TOTAL_KEY__, false, "", "Errors detected in global device memory."},
+		{__ECC_ERRORS_CORRECTED_TOTAL_CHIP_KEY__, true, "", "Errors detected in the APU%d"},
+		{__ECC_ERRORS_UNCORRECTED_TOTAL_KEY__, false, "", "Errors detected in global device memory."},
+		{__ECC_ERRORS_UNCORRECTED_TOTAL_CHIP_KEY__, true, "", "Errors detected in the APU%d."},
+	}
+	chipQFieldRegexp = regexp.MustCompile(`^(.+\.chip)(\d+)$`)
+)
+
+const (
+	__TIMESTAMP_KEY__                          = "timestamp"
+	__BOARD_INDEX_KEY__                        = "board_index"
+	__PRODUCT_NAME_KEY__                       = "name"
+	__PRODUCT_NUMBER_KEY__                     = "product_number"
+	__DRIVER_VERSION_KEY__                     = "driver_version"
+	__FIRMWARE_VERSION_KEY__                   = "firmware_version"
+	__SERIAL_NUMBER_KEY__                      = "serial_number"
+	__CHIP_COUNT_KEY__                         = "chip_count"
+	__CHIP_ID_LIST_KEY__                       = "chip_id_list"
+	__CHIP_ID_CHIP_KEY__                       = "chip_id.chip"
+	__UUID_LIST_KEY__                          = "uuid_list"
+	__UUID_CHIP_KEY__                          = "uuid.chip"
+	__CHIP_INDEX_LIST_KEY__                    = "chip_index_list"
+	__CHIP_INDEX_CHIP_KEY__                    = "chip_index.chip"
+	__UTILIZATION_APU_TOTAL_KEY__              = "utilization.apu.total"
+	__UTILIZATION_APU_CHIPS_KEY__              = "utilization.apu.chips"
+	__UTILIZATION_APU_CHIP_KEY__               = "utilization.apu.chip"
+	__UTILIZATION_CPU_TOTAL_KEY__              = "utilization.cpu.total"
+	__UTILIZATION_CPU_CHIPS_KEY__              = "utilization.cpu.chips"
+	__UTILIZATION_CPU_CHIP_KEY__               = "utilization.cpu.chip"
+	__UTILIZATION_VIC_TOTAL_KEY__              = "utilization.vic.total"
+	__UTILIZATION_VIC_CHIPS_KEY__              = "utilization.vic.chips"
+	__UTILIZATION_VIC_CHIP_KEY__               = "utilization.vic.chip"
+	__UTILIZATION_MEMORY_TOTAL_KEY__           = "utilization.memory.total"
+	__UTILIZATION_MEMORY_CHIPS_KEY__           = "utilization.memory.chips"
+	__UTILIZATION_MEMORY_CHIP_KEY__            = "utilization.memory.chip"
+	__UTILIZATION_IPE_FPS_TOTAL_KEY__          = "utilization.ipeFps.total"
+	__UTILIZATION_IPE_FPS_CHIPS_KEY__          = "utilization.ipeFps.chips"
+	__UTILIZATION_IPE_FPS_CHIP_KEY__           = "utilization.ipeFps.chip"
+	__PCI_CHIPS__KEY__                         = "pci.chips"
+	__PCI_VENDOR_ID_KEY__                      = "pci.vendor_id"
+	__PCI_VENDOR_ID_CHIP_KEY__                 = "pci.vendor_id.chip"
+	__PCI_SUB_VENDOR_ID_KEY__                  = "pci.sub_vendor_id"
+	__PCI_SUB_VENDOR_ID_CHIP_KEY__             = "pci.sub_vendor_id.chip"
+	__PCI_BUS_KEY__                            = "pci.bus"
+	__PCI_BUS_CHIP_KEY__                       = "pci.bus.chip"
+	__PCI_DEVICE_ID_KEY__                      = "pci.device_id"
+	__PCI_DEVICE_ID_CHIP_KEY__                 = "pci.device_id.chip"
+	__PCI_SUB_DEVICE_ID_KEY__                  = "pci.sub_device_id"
+	__PCI_SUB_DEVICE_ID_CHIP_KEY__             = "pci.sub_device_id.chip"
+	__PCI_DEVICE_KEY__                         = "pci.device"
+	__PCI_DEVICE_CHIP_KEY__                    = "pci.device.chip"
+	__PCI_SUB_FUNCTION_KEY__                   = "pci.function"
+	__PCI_SUB_FUNCTION_CHIP_KEY__              = "pci.function.chip"
+	__PCI_NUMA_NODE_ID_KEY__                   = "pci.numa.node_id"
+	__PCI_NUMA_NODE_ID_CHIP_KEY__              = "pci.numa.node_id.chip"
+	__PCI_NUMA_CPU_KEY__                       = "pci.numa.cpu"
+	__PCI_NUMA_CPU_CHIP_KEY__                  = "pci.numa.cpu.chip"
+	__PCIE_LINK_SPEED_MAX_KEY__                = "pcie.link.speed.max"
+	__PCIE_LINK_SPEED_MAX_CHIP_KEY__           = "pcie.link.speed.max.chip"
+	__PCIE_LINK_SPEED_CURRENT_KEY__            = "pcie.link.speed.current"
+	__PCIE_LINK_SPEED_CURRENT_CHIP_KEY__       = "pcie.link.speed.current.chip"
+	__PCIE_LINK_GEN_MAX_KEY__                  = "pcie.link.gen.max"
+	__PCIE_LINK_GEN_MAX_CHIP_KEY__             = "pcie.link.gen.max.chip"
+	__PCIE_LINK_GEN_CURRENT_KEY__              = "pcie.link.gen.current"
+	__PCIE_LINK_GEN_CURRENT_CHIP_KEY__         = "pcie.link.gen.current.chip"
+	__FAN_SPEED_KEY__                          = "fan.speed"
+	__TEMPERATURE_CURRENT_CHIPS_KEY__          = "temperature.current.chips"
+	__TEMPERATURE_CURRENT_CHIP_KEY__           = "temperature.current.chip"
+	__TEMPERATURE_SLOWDOWN_CHIPS_KEY__         = "temperature.slowdown.chips"
+	__TEMPERATURE_SLOWDOWN_CHIP_KEY__          = "temperature.slowdown.chip"
+	__TEMPERATURE_SHUTDOWN_CHIPS_KEY__         = "temperature.shutdown.chips"
+	__TEMPERATURE_SHUTDOWN_CHIP_KEY__          = "temperature.shutdown.chip"
+	__TEMPERATURE_HEADROOM_CHIP_KEY__          = "temperature.headroom.chip"
+	__VOLTAGE_CURRENT_CHIPS_KEY__              = "voltage.current.chips"
+	__VOLTAGE_CURRENT_CHIP_KEY__               = "voltage.current.chip"
+	__VOLTAGE_BOARD_INPUT_KEY__                = "voltage.board.input"
+	__CLOCKS_KEY__                             = "clocks"
+	__CLOCKS_CURRENT_APU_CHIPS_KEY__           = "clocks.current.apu.chips"
+	__CLOCKS_CURRENT_APU_KEY__                 = "clocks.current.apu"
+	__CLOCKS_CURRENT_APU_CHIP_KEY__            = "clocks.current.apu.chip"
+	__CLOCKS_CURRENT_CPU_CHIPS_KEY__           = "clocks.current.cpu.chips"
+	__CLOCKS_CURRENT_CPU_KEY__                 = "clocks.current.cpu"
+	__CLOCKS_CURRENT_CPU_CHIP_KEY__            = "clocks.current.cpu.chip"
+	__CLOCKS_CURRENT_MEMORY_CHIPS_KEY__        = "clocks.current.memory.chips"
+	__CLOCKS_CURRENT_MEMORY_KEY__              = "clocks.current.memory"
+	__CLOCKS_CURRENT_MEMORY_CHIP_KEY__         = "clocks.current.memory.chip"
+	__CLOCKS_MAX_APU_CHIPS_KEY__               = "clocks.current.apu.max.chips"
+	__CLOCKS_MAX_APU_CHIP_KEY__                = "clocks.current.apu.max.chip"
+	__CLOCKS_MAX_CPU_CHIPS_KEY__               = "clocks.current.cpu.max.chips"
+	__CLOCKS_MAX_CPU_CHIP_KEY__                = "clocks.current.cpu.max.chip"
+	__CLOCKS_MAX_MEMORY_CHIPS_KEY__            = "clocks.current.memory.max.chips"
+	__CLOCKS_MAX_MEMORY_CHIP_KEY__             = "clocks.current.memory.max.chip"
+	__CLOCKS_THROTTLE_ACTIVE_CHIP_KEY__        = "clocks_throttle_reasons.active.chip"
+	__CLOCKS_THROTTLE_THERMAL_CHIP_KEY__       = "clocks_throttle_reasons.thermal.chip"
+	__CLOCKS_THROTTLE_POWER_CAP_CHIP_KEY__     = "clocks_throttle_reasons.power_cap.chip"
+	__CLOCKS_THROTTLE_IDLE_CHIP_KEY__          = "clocks_throttle_reasons.idle.chip"
+	__CLOCKS_THROTTLE_UNKNOWN_CHIP_KEY__       = "clocks_throttle_reasons.unknown.chip"
+	__POWER_DRAW__                             = "power.draw"
+	__POWER_LIMIT__                            = "power.limit"
+	__ECC_MODE_CURRENT_CHIPS_KEY__             = "ecc.mode.current.chips"
+	__ECC_MODE_CURRENT_CHIP_KEY__              = "ecc.mode.current.chip"
+	__ECC_ERRORS_CORRECTED_TOTAL_KEY__         = "ecc.errors.corrected.total"
+	__ECC_ERRORS_CORRECTED_TOTAL_CHIPS_KEY__   = "ecc.errors.corrected.total.chips"
+	__ECC_ERRORS_CORRECTED_TOTAL_CHIP_KEY__    = "ecc.errors.corrected.total.chip"
+	__ECC_ERRORS_UNCORRECTED_TOTAL_KEY__       = "ecc.errors.uncorrected.total"
+	__ECC_ERRORS_UNCORRECTED_TOTAL_CHIPS_KEY__ = "ecc.errors.uncorrected.total.chips"
+	__ECC_ERRORS_UNCORRECTED_TOTAL_CHIP_KEY__  = "ecc.errors.uncorrected.total.chip"
+)
+
+type BoardBaseInfo struct {
+	TimeStamp                     string               `json:"timestamp"`
+	BoardIndex                    string               `json:"board_index"`
+	ProductName                   string               `json:"name"`
+	ProductBrand                  string               `json:"product_brand"`
+	ProductNumber                 string               `json:"product_number"`
+	DriverVersion                 string               `json:"driver_version"`
+	FirmwareVersion               string               `json:"firmware_version"`
+	SerialNumber                  string               `json:"serial_number"`
+	UuidList                      []string             `json:"uuid_list"`
+	ChipCount                     string               `json:"chip_count"`
+	ChipIdList                    []string             `json:"chip_id_list"`
+	ChipIndexList                 []string             `json:"chip_index_list"`
+	ApuTotal                      string               `json:"utilization.apu.total"`
+	ApuUtilList                   []string             `json:"utilization.apu.chips"`
+	CpuTotal                      string               `json:"utilization.cpu.total"`
+	CpuUtilList                   []string             `json:"utilization.cpu.chips"`
+	VicTotal                      string               `json:"utilization.vic.total"`
+	VicUtilList                   []string             `json:"utilization.vic.chips"`
+	MemoryTotal                   string               `json:"utilization.memory.total"`
+	MemoryUtilList                []string             `json:"utilization.memory.chips"`
+	IpeTotal                      string               `json:"utilization.ipeFps.total"`
+	IpeUtilList                   []string             `json:"utilization.ipeFps.chips"`
+	TempUtilList                  []string             `json:"temperature.current.chips"`
+	TempSlowdownList              []string             `json:"temperature.slowdown.chips"`
+	TempShutdownList              []string             `json:"temperature.shutdown.chips"`
+	FanSpeed                      string               `json:"fan.speed"`
+	ChipVoltageList               []string             `json:"voltage.current.chips"`
+	VoltageInput                  string               `json:"voltage.board.input"`
+	PowerDraw                     string               `json:"power.draw"`
+	PowerLimit                    string               `json:"power.limit"`
+	EccModeList                   []string             `json:"ecc.mode.current.chips"`
+	DdrEccCorrectedTotal          string               `json:"ecc.errors.corrected.total"`
+	DdrEccUnCorrectedTotal        string               `json:"ecc.errors.uncorrected.total"`
+	DdrEccErrCorrectedChipCount   []string             `json:"ecc.errors.corrected.total.chips"`
+	DdrEccErrUnCorrectedChipCount []string             `json:"ecc.errors.uncorrected.total.chips"`
+	PciInfoList                   []BoardPciDeviceInfo `json:"pci.chips"`
+	ClocksInfo                    BoardClocksInfo      `json:"clocks"`
+}
+
+type BoardClocksInfo struct {
+	ApuClocksList       []string `json:"clocks.current.apu.chips"`
+	CpuClocksList       []string `json:"clocks.current.cpu.chips"`
+	MemoryClocksList    []string `json:"clocks.current.memory.chips"`
+	ApuClocksMaxList    []string `json:"clocks.current.apu.max.chips"`
+	CpuClocksMaxList    []string `json:"clocks.current.cpu.max.chips"`
+	MemoryClocksMaxList []string `json:"clocks.current.memory.max.chips"`
+}
+
+type BoardPciDeviceInfo struct {
+	VendorId     string `json:"pci.vendor_id"`
+	DeviceId     string `json:"pci.device_id"`
+	SubVendorId  string `json:"pci.sub_vendor_id"`
+	SubDeviceId  string `json:"pci.sub_device_id"`
+	BusNum       string `json:"pci.bus"`
+	Device       string `json:"pci.device"`
+	Function     string `json:"pci.function"`
+	MaxSpeed     string `json:"pcie.link.speed.max"`
+	MaxWidth     string `json:"pcie.link.gen.max"`
+	CurrentSpeed string `json:"pcie.link.speed.current"`
+	CurrentWidth string `json:"pcie.link.gen.current"`
+	NumaNodeId   string `json:"pci.numa.node_id"`
+	NumaCPUList  string `json:"pci.numa.cpu"`
+}
+
+func (pdi *BoardPciDeviceInfo) Set(vendorId string, deviceId string, subVendorId string,
+	subDeviceId string, busNum string, device string, function string, maxSpeed string,
+	maxWidth string, currentSpeed string, currentWidth string, numaNodeId string, numCPUList string) {
+	pdi.VendorId = vendorId
+	pdi.DeviceId = deviceId
+	pdi.SubVendorId = subVendorId
+	pdi.SubDeviceId = subDeviceId
+	pdi.BusNum = busNum
+	pdi.Device = device
+	pdi.Function = function
+	pdi.MaxSpeed = maxSpeed
+	pdi.MaxWidth = maxWidth
+	pdi.CurrentSpeed = currentSpeed
+	pdi.CurrentWidth = currentWidth
+	pdi.NumaNodeId = numaNodeId
+	pdi.NumaCPUList = numCPUList
+}
+
+type Snapshot struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Boards    []BoardBaseInfo `json:"boards"`
+}
+
+type BoardSummary struct {
+	BoardIndex   int    `json:"board_index"`
+	ProductName  string `json:"name"`
+	SerialNumber string `json:"serial_number"`
+	ChipCount    int    `json:"chip_count"`
+}
+
+type FieldHelp struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+}
+
+const (
+	DefaultPciDevicesInfoPath = "/sys/bus/pci/devices/0000:"
+	NumaNode                  = "numa_node"
+	NumaNodeCPUList           = "local_cpulist"
+	CurrentLinkSpeed          = "current_link_speed"
+	CurrentLinkWidth          = "current_link_width"
+	MaxLinkWidth              = "max_link_width"
+	MaxLinkSpeed              = "max_link_speed"
+	DeviceID                  = "device"
+	VendorID                  = "vendor"
+	SubSystemDevice           = "subsystem_device"
+	SubSystemVendor           = "subsystem_vendor"
+)
+
+const (
+	__BOARD_STR__             = "Board:"
+	__PRODUCT_NAME_STR__      = "Product Name"
+	__PRODUCT_BRAND_STR__     = "Product Brand"
+	__PRODUCT_NUMBER_STR__    = "Product Number"
+	__DRIVER_STR__            = "Driver Version"
+	__FIRMWARE_VERSION_STR__  = "Firmware Version"
+	__SERIAL_NUMBER_STR__     = "Serial Number"
+	__CHIP_COUNT_STR__        = "Chip Count"
+	__CHIP_INDEX_STR__        = "Chip Index"
+	__CHIP_ID_STR__           = "Chip ID"
+	__ECID_STR__              = "ECID"
+	__UUID_STR__              = "UUID"
+	__UTILIZATION_STR__       = "Utilization"
+	__APU_STR__               = "APU"
+	__TOTAL_STR__             = "Total"
+	__CPU_STR__               = "CPU"
+	__VIC_STR__               = "VIC"
+	__MEMORY_STR__            = "Memory"
+	__IPE_FPS_STR__           = "IPE-FPS"
+	__IPE_STR__               = "IPE"
+	__PCIE_STR__              = "PCIE"
+	__PCI_STR__               = "PCI"
+	__VENDOR_ID_STR__         = "Vendor ID"
+	__DEVICE_ID_STR__         = "Device ID"
+	__SUB_DEVICE_ID_STR__     = "Sub Device ID"
+	__SUB_Vendor_ID_STR__     = "Sub Vendor ID"
+	__PCIE_GEN_STR__          = "PCIe Generation"
+	__GENERATION_STR__        = "Generation"
+	__MAX_STR__               = "Max"
+	__CURRENT__               = "Current"
+	__BUS_NUM__               = "Bus num"
+	__DEVICE__                = "Device"
+	__FUNCTION__              = "Function"
+	__PHYSICAL_SLOT__         = "Physical Slot"
+	__NUMA_NODE_ID__          = "NUMA node id"
+	__FAN_STR__               = "Fan"
+	__TEMPERATURE_STR__       = "Temperature"
+	__BIU_CURRENT_TEMP_STR__  = "BIU Current Temp"
+	__BIU_SLOWDOWN_TEMP_STR__ = "BIU Slowdown Temp"
+	__BIU_SHUTDOWN_TEMP_STR__ = "BIU Shutdown Temp"
+	__VOLTAGE_STR__           = "Voltage"
+	__CHIP_VOLTAGE__STR__     = "Chip Voltage"
+	__BOARD_VOLTAGE_STR__     = "Board Voltage"
+	__CLOCKS_STR__            = "Clocks"
+	__INPUT_STR__             = "Input"
+	__PCIE_SWITCH__           = "PCIe Switch"
+	__APU_CLOCK_STR__         = "APU Clock"
+	__APU_MAX_CLOCK_STR__     = "APU Max Clock"
+	__CPU_CLOCK_STR__         = "CPU Clock"
+	__CPU_MAX_CLOCK_STR__     = "CPU Max Clock"
+	__MEMORY_CLOCK_STR__      = "Memory Clock"
+	__MEMORY_MAX_CLOCK_STR__  = "Memory Max Clock"
+	__POWER_STR__             = "Power"
+	__POWER_DRAW_STR__        = "Power Draw"
+	__POWER_LIMIT_STR__       = "Power Limit"
+	__ECC_MODE_STR__          = "ECC Mode"
+	__DDR_ECC_ERR_COUNT_STR__ = "DDR ECC Err Count"
+	__CORRECTED_ERR_STR__     = "Corrected Err"
+	__UNCORRECTED_ERR_STR__   = "Uncorrected Err"
+	__CHIP0_STR__             = "Chip0"
+	__CHIP1_STR__             = "Chip1"
+	__CHIP2_STR__             = "Chip2"
+	__CHIP0_COLON_STR__       = "Chip0"
+	__CHIP1_COLON_STR__       = "Chip1"
+	__CHIP2_COLON_STR__       = "Chip2"
+	__CHIP_STR__              = "Chip"
+)
+
+const (
+	__SN_STR__      = "SN"
+	__START_STR__   = "***"
+	__ERROR_STR__   = "ERROR"
+	__SUB_STR__     = "Sub"
+	__NA_STR__      = "NA"
+	__N_A_STR__     = "N/A"
+	__UNKNOWN_STR__ = "unknown"
+	__APU_SMI_STR__ = "APU-SMI"
+)
+
+var (
+	VersionCmdParam                    []string = []string{"-l", "|", "grep", "-i"}
+	LynChipDeviceIDAndVendorIDCmdParam []string = strings.Split("-d 1e9f:27c5", __SPCAE_SEP__)
+	LynSmiDetailInfoCmdParam           string   = "-q"
+	LynSmiCardIdCmdParam               string   = "-i"
+	LynSmiChipIdCmdParam               string   = "-c"
+	LynSmiVersionCmdParam              string   = "-v"
+)
+
+func toQFieldSlice(ss []string) []qField {
+	r := make([]qField, len(ss))
+	for i, s := range ss {
+		r[i] = qField(s)
+	}
+	return r
+}
+
+func getKeys(m map[qField]rField) []qField {
+	r := make([]qField, len(m))
+	i := 0
+	for key := range m {
+		r[i] = key
+		i++
+	}
+	return r
+}
+
+func parserVersionStr(v string) (string, error) {
+	re := regexp.MustCompile(`^ii*\s+\w+\s+(\d+\.\d+\.\d+?)\s+\w*`)
+	match := re.FindStringSubmatch(v)
+	if match == nil {
+		return "", errors.New(LynDriverStr + " version not found")
+	}
+	return VersionShortStr + string(match[1]), nil
+}
+
+func isStrBlank(str string) bool {
+	return str == __SPCAE_SEP__
+}
+
+func isDebugInfo(line string) bool {
+	return strings.Contains(line, __ERROR_STR__) || strings.Contains(line, "lynSmi.cpp") || strings.Contains(line, __SN_STR__) || strings.Contains(line, __START_STR__)
+}
+
+// removeDebugInfo skips the banner and debug lines before the report, err is the one of reading line and the
+// returned error the one of reading the first line of the report, io.EOF when the output has only the banner.
+func removeDebugInfo(line *string, r *bufio.Reader, err error) error {
+	for err == nil && isDebugInfo(*line) {
+		log.Debugln(*line)
+		*line, err = r.ReadString(__LINE_FEED_SEP__)
+	}
+	return err
+}
+
+// getVersion caches the versions for the data source, they do not change while the process runs.
+func getVersion(v Version) string {
+	versionCacheLock.Lock()
+	defer versionCacheLock.Unlock()
+	if version, ok := versionCache[v]; ok {
+		return version
+	}
+	version := queryVersion(v)
+	if version != "" && version != __UNKNOWN_STR__ {
+		versionCache[v] = version
+	}
+	return version
+}
+
+func resetSourceCache() {
+	versionCacheLock.Lock()
+	versionCache = make(map[Version]string)
+	versionCacheLock.Unlock()
+	chipCountLock.Lock()
+	defer chipCountLock.Unlock()
+	chipCountCache = 0
+}
+
+func queryVersion(v Version) string {
+	switch v {
+	case SDK:
+		sdkVersion, err := currentSource.SdkVersion()
+		if err != nil {
+			log.Debugln(err)
+			break
+		}
+		return sdkVersion
+	case Driver:
+		driverVersion, err := currentSource.DriverVersion()
+		if err != nil {
+			log.Debugln(err)
+			break
+		}
+		return driverVersion
+	case SMI:
+		smiVersion, err := currentSource.SmiVersion()
+		if err != nil {
+			log.Debugln(err)
+			break
+		}
+		return smiVersion
+	}
+
+	return __UNKNOWN_STR__
+}
+
+func replaceNAInfo(line string) string {
+	if strings.Contains(line, __NA_STR__) {
+		return strings.Replace(line, __NA_STR__, __N_A_STR__, -1)
+	} else {
+		return line
+	}
+}
+
+func replaceDriverVersionInfo(line string) string {
+	if strings.Contains(line, __DRIVER_STR__) {
+		driverInfoSlice := strings.Split(line, __SPCAE_SEP__)
+		driverInfoSlice[len(driverInfoSlice)-1] = getVersion(Driver) + "\n"
+		return strings.Join(driverInfoSlice, __SPCAE_SEP__)
+	} else {
+		return line
+	}
+}
+
+func replaceProductNameToSmiVersionName(line string) string {
+	if strings.Contains(line, __PRODUCT_NAME_STR__) {
+		line = strings.Replace(line, __PRODUCT_NAME_STR__, __APU_SMI_STR__, -1)
+		strSlice := strings.Split(line, __SPCAE_SEP__)
+		return strings.Replace(line, string(strSlice[1]), getVersion(SMI), -1)
+	} else {
+		return line
+	}
+}
+
+func replaceECIDInfoToChipIndex(w io.Writer, line string, chipCount int, r *bufio.Reader, chipIndex int) string {
+	if strings.Contains(line, __ECID_STR__) {
+		line = strings.Replace(line, __ECID_STR__, __CHIP_INDEX_STR__, -1)
+		fmt.Fprint(w, line)
+		for i := 0; i < chipCount; i++ {
+			line, _ = r.ReadString(__LINE_FEED_SEP__)
+			uuidInfoSlice := strings.Split(line, __SPCAE_SEP__)
+			uuidInfoSlice[len(uuidInfoSlice)-1] = strconv.Itoa(chipIndex) + "\n"
+			line = strings.Join(uuidInfoSlice, __SPCAE_SEP__)
+			chipIndex++
+			fmt.Fprint(w, line)
+		}
+		line, _ = r.ReadString(__LINE_FEED_SEP__)
+		return line
+	} else {
+		return line
+	}
+}
+
+func replacePciInfo(line string, deviceInfo string) string {
+	str_slice := strings.Split(line, __SPCAE_SEP__)
+	str_slice[len(str_slice)-1] = deviceInfo
+	return strings.Join(str_slice, __SPCAE_SEP__)
+}
+
+func updatePciInfo(line string, pciChipIndex int, chipIndex int, pciInfoStrList []string) string {
+	switch {
+	case strings.Contains(line, __PCIE_STR__) && !strings.Contains(line, __GENERATION_STR__):
+		return strings.Replace(line, __PCIE_STR__, __PCI_STR__, -1)
+	case strings.Contains(line, __VENDOR_ID_STR__) && !strings.Contains(line, __SUB_STR__):
+		vendorId, _ := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, VendorId)
+		return replacePciInfo(line, vendorId)
+	case strings.Contains(line, __DEVICE_ID_STR__) && !strings.Contains(line, __SUB_STR__):
+		deviceId, _ := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, DeviceId)
+		return replacePciInfo(line, deviceId)
+	case strings.Contains(line, __SUB_Vendor_ID_STR__) && strings.Contains(line, __SUB_STR__):
+		subVendorId, _ := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, SubVendorId)
+		return replacePciInfo(line, subVendorId)
+	case strings.Contains(line, __SUB_DEVICE_ID_STR__) && strings.Contains(line, __SUB_STR__):
+		subDeviceId, pciDeviceInfo := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, SubDeviceId)
+		maxSpeed, _ := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, MaxSpeed)
+		currentSpeed, _ := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, CurrentSpeed)
+		numaNodeId, _ := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, NumaNodeId)
+		numaCpuList, _ := getPciDeviceInfo(pciChipIndex, chipIndex, pciInfoStrList, NumaCPUList)
+		pciInfoSlice := strings.Split(pciDeviceInfo, __COLON_SEP__)
+		devAndFunc := strings.Split(pciInfoSlice[1], __DOT_SEP__)
+		str_slice := strings.Split(line, __SPCAE_SEP__)[0:8]
+		space := strings.Join(str_slice, __SPCAE_SEP__)
+		pciInfo := space + " Bus Num:" + space + space + pciInfoSlice[0] + "\n" + space + " Device: " + space + space + devAndFunc[0] + "\n" + space + " Function:      " + space + devAndFunc[1] + "\n"
+		pcieInfo := space + " Max Speed:     " + space + maxSpeed + space + " Current Speed: " + space + currentSpeed + space + " NumaNodeId:    " + space + numaNodeId + space + " NumaCpuList:   " + space + numaCpuList
+		return replacePciInfo(line, subDeviceId) + pciInfo + pcieInfo
+	}
+	return line
+}
+
+func queryPciDeviceInfoByDeviceID(devicePciInfo string, info PciInfo) string {
+	switch info {
+	case VendorId:
+		return readPciDeviceAttr(devicePciInfo, VendorID)
+	case DeviceId:
+		return readPciDeviceAttr(devicePciInfo, DeviceID)
+	case SubVendorId:
+		return readPciDeviceAttr(devicePciInfo, SubSystemVendor)
+	case SubDeviceId:
+		return readPciDeviceAttr(devicePciInfo, SubSystemDevice)
+	case BusNum:
+		return strings.Split(devicePciInfo, __COLON_SEP__)[0]
+	case Device:
+		pciInfoSlice := strings.Split(devicePciInfo, __COLON_SEP__)
+		return strings.Split(pciInfoSlice[1], __DOT_SEP__)[0]
+	case Function:
+		pciInfoSlice := strings.Split(devicePciInfo, __COLON_SEP__)
+		return strings.Split(pciInfoSlice[1], __DOT_SEP__)[1]
+	case MaxSpeed:
+		return readPciDeviceAttr(devicePciInfo, MaxLinkSpeed)
+	case MaxWidth:
+		return readPciDeviceAttr(devicePciInfo, MaxLinkWidth)
+	case CurrentSpeed:
+		return readPciDeviceAttr(devicePciInfo, CurrentLinkSpeed)
+	case CurrentWidth:
+		return readPciDeviceAttr(devicePciInfo, CurrentLinkWidth)
+	case NumaNodeId:
+		return readPciDeviceAttr(devicePciInfo, NumaNode)
+	case NumaCPUList:
+		return readPciDeviceAttr(devicePciInfo, NumaNodeCPUList)
+	}
+	return fmt.Sprintf("%s get failed", devicePciInfo)
+}
+
+func readPciDeviceAttr(devicePciInfo string, attr string) string {
+	val, err := currentSource.PciDeviceAttr(__PCI_DOMAIN__+__COLON_SEP__+devicePciInfo, attr)
+	if err != nil {
+		log.Debugln(err)
+	}
+	return val + __LINE_FEED_STR__
+}
+
+func getBoardIndex(line string) (string, int) {
+	if strings.Contains(line, __BOARD_STR__) {
+		boardIndexSlice := strings.Split(line, __COLON_SEP__)
+		return strings.TrimSpace(boardIndexSlice[len(boardIndexSlice)-1]), 1
+	}
+	return line, -1
+}
+
+func computeCurrentChipIndex(boardIndex int, chipCount int) int {
+	return (boardIndex+1)*chipCount - chipCount
+}
+
+func getPciDeviceInfo(pciChipIndex int, chipIndex int, pciInfoStrList []string, info PciInfo) (string, string) {
+	index := chipIndex + pciChipIndex
+	if pciChipIndex < 0 || index >= len(pciInfoStrList) {
+		log.Debugln(pciInfoStrList)
+		return "get info failed", "get info failed"
+	}
+	PciDeviceInfo := pciInfoStrList[index]
+	return queryPciDeviceInfoByDeviceID(PciDeviceInfo, info), PciDeviceInfo
+}
+
+func removeLineBreak(info string) string {
+	return strings.Replace(info, __LINE_FEED_STR__, "", -1)
+}
+
+func removePciInfoUnit(info string) string {
+	return strings.Split(info, __SPCAE_SEP__)[0]
+}
+func computePciChipIndex(line string, currentBoardIndex *int, boardIndex *int, pciChipIndex *int) {
+	if strings.Contains(line, __VENDOR_ID_STR__) && !strings.Contains(line, __SUB_STR__) {
+		if *currentBoardIndex == *boardIndex {
+			*pciChipIndex++
+		} else {
+			*currentBoardIndex = *boardIndex
+			*pciChipIndex = 0
+		}
+	}
+}
+
+func getClocksVal(r *bufio.Reader, infoList []string, index int) {
+	line, _ := r.ReadString(__LINE_FEED_SEP__)
+	infoList[index] = getBoardInfoVal(line)
+}
+
+func getBoardUtilTotalVal(utilStr string) string {
+	indexVal := 1
+	if strings.Contains(utilStr, __PER_SEP__) {
+		indexVal = 2
+	}
+	utilStrSlice := strings.Split(utilStr, __SPCAE_SEP__)
+	return replaceNAVal(strings.TrimSpace(utilStrSlice[len(utilStrSlice)-indexVal]))
+}
+
+func getBoardUtilInfo(line string, chipCount string, r *bufio.Reader) []string {
+	count, _ := strconv.Atoi(chipCount)
+	var utilStrList []string
+	for i := 0; i < count; i++ {
+		line, _ = r.ReadString(__LINE_FEED_SEP__)
+		boardUtilInfo := getBoardUtilTotalVal(line)
+		utilStrList = append(utilStrList, boardUtilInfo)
+	}
+	return utilStrList
+}
+
+func getChipIndexByChipCountAndBoardIndex(chipStartIndex int, chipCount string) []string {
+	count, _ := strconv.Atoi(chipCount)
+	var chipIndexList []string
+	for i := chipStartIndex; i < chipStartIndex+count; i++ {
+		chipIndex := strconv.Itoa(i)
+		chipIndexList = append(chipIndexList, chipIndex)
+	}
+	return chipIndexList
+}
+
+func getChipCount(r *bufio.Reader, line *string, fn func(line *string)) int {
+	var chipIdList []string
+	fn(line)
+	for {
+		*line, _ = r.ReadString(__LINE_FEED_SEP__)
+		if strings.Contains(*line, __CHIP_STR__) {
+			fn(line)
+			chipIdList = append(chipIdList, getBoardInfoVal(*line))
+		} else {
+			break
+		}
+	}
+	return len(chipIdList)
+}
+
+func getChipCountByChipId(r *bufio.Reader, line *string) int {
+	fn := func(line *string) {
+	}
+	return getChipCount(r, line, fn)
+}
+
+func getChipCountByChipIdAndPrint(w io.Writer, r *bufio.Reader, line *string) int {
+	fn := func(line *string) {
+		fmt.Fprint(w, *line)
+	}
+	return getChipCount(r, line, fn)
+}
+
+func getChipIdListByChipCount(r *bufio.Reader, line *string) ([]string, string) {
+	var chipIdList []string
+	for {
+		*line, _ = r.ReadString(__LINE_FEED_SEP__)
+		if strings.Contains(*line, __CHIP_STR__) {
+			chipIdList = append(chipIdList, getBoardInfoVal(*line))
+		} else {
+			break
+		}
+	}
+	_chipCount := strconv.Itoa(len(chipIdList))
+	return chipIdList, _chipCount
+}
+
+func getBoardInfoVal(infoStr string) string {
+	var (
+		infoStrSlice []string
+		val          string
+	)
+	if strings.Contains(infoStr, __COLON_SEP__) {
+		infoStrSlice = strings.Split(infoStr, __COLON_SEP__)
+		val = strings.TrimSpace(infoStrSlice[len(infoStrSlice)-1])
+	} else {
+		infoStrSlice = strings.Split(infoStr, __SPCAE_SEP__)
+		_index := len(infoStrSlice) - 2
+		if strings.Contains(infoStr, __UNCORRECTED_ERR_STR__) && !strings.Contains(infoStr, __COLON_SEP__) {
+			_index = len(infoStrSlice) - 1
+		}
+		val = strings.TrimSpace(infoStrSlice[_index])
+	}
+	if strings.Contains(val, __V_SEP__) || strings.Contains(val, __C_SEP__) {
+		return strings.Split(val, __SPCAE_SEP__)[0]
+	}
+	return replaceNAVal(val)
+}
+
+func replaceNAVal(val string) string {
+	if val == __NA_STR__ {
+		return __N_A_STR__
+	}
+	return val
+}
+
+func removeDuplicateQFields(qFields []qField) []qField {
+	m := make(map[qField]struct{})
+	var r []qField
+	for _, f := range qFields {
+		_, exists := m[f]
+		if !exists {
+			r = append(r, f)
+			m[f] = struct{}{}
+		}
+	}
+	return r
+}
+
+func getBoardSN(line string) (string, int) {
+	if strings.Contains(line, __SERIAL_NUMBER_STR__) {
+		boardSnSlice := strings.Split(line, __COLON_SEP__)
+		return strings.TrimSpace(boardSnSlice[len(boardSnSlice)-1]), 1
+	}
+	return line, -1
+}
+
+func getBoardPN(line string) (string, int) {
+	if strings.Contains(line, __PRODUCT_NAME_STR__) {
+		boardSnSlice := strings.Split(line, __COLON_SEP__)
+		return strings.TrimSpace(boardSnSlice[len(boardSnSlice)-1]), 1
+	}
+	return line, -1
+}
+
+func removeAndReplaceBaseInfo(line string) string {
+	line = replaceNAInfo(line)
+	line = replaceDriverVersionInfo(line)
+	return line
+}
+
+func buildQFields(chipCount int) []qField {
+	var r []qField
+	for _, t := range qFieldTemplates {
+		if !t.perChip {
+			r = append(r, t.name)
+			continue
+		}
+		for i := 0; i < chipCount; i++ {
+			r = append(r, qField(getMapDataKeyIndex(string(t.name), i)))
+		}
+	}
+	return r
+}
+
+func lookupQFieldTemplate(f qField) (qFieldTemplate, int, bool) {
+	for _, t := range qFieldTemplates {
+		if !t.perChip && t.name == f {
+			return t, -1, true
+		}
+	}
+	match := chipQFieldRegexp.FindStringSubmatch(string(f))
+	if match != nil {
+		index, _ := strconv.Atoi(match[2])
+		for _, t := range qFieldTemplates {
+			if t.perChip && string(t.name) == match[1] {
+				return t, index, true
+			}
+		}
+	}
+	return qFieldTemplate{}, -1, false
+}
+
+func getQFieldComment(f qField) string {
+	t, index, exists := lookupQFieldTemplate(f)
+	if !exists {
+		return ""
+	}
+	if t.perChip {
+		return fmt.Sprintf(t.comment, index)
+	}
+	return t.comment
+}
+
+func getQFieldUnit(f qField) string {
+	t, _, _ := lookupQFieldTemplate(f)
+	return t.unit
+}
+
+func detectChipCount() int {
+	chipCount := queryMaxChipCount()
+	if chipCount == 0 {
+		return defaultChipCount
+	}
+	return chipCount
+}
+
+// queryMaxChipCount returns the largest chip count of the boards, 0 when no board is reported. It is cached for the
+// data source like the versions, so the fields are not checked with another lynxi-smi -q on every query.
+func queryMaxChipCount() int {
+	chipCountLock.Lock()
+	defer chipCountLock.Unlock()
+	if chipCountCache > 0 {
+		return chipCountCache
+	}
+	chipCount := 0
+	boardSummaryList, err := ListBoards()
+	if err != nil {
+		log.Debugln(err)
+	}
+	for _, b := range boardSummaryList {
+		if b.ChipCount > chipCount {
+			chipCount = b.ChipCount
+		}
+	}
+	chipCountCache = chipCount
+	return chipCount
+}
+
+// verifyAndCheckQueryFields also rejects the per chip fields beyond the largest chip count of the boards, they are
+// only accepted when no board is reported.
+func verifyAndCheckQueryFields(qFieldsRaw string) ([]qField, error) {
+	qFieldsSeparated := strings.Split(qFieldsRaw, __COMMA_SEP__)
+	qFields := toQFieldSlice(qFieldsSeparated)
+	qFields = removeDuplicateQFields(qFields)
+	chipCount := -1
+	for _, f := range qFields {
+		t, index, exists := lookupQFieldTemplate(f)
+		if !exists {
+			return qFields, fmt.Errorf("field %s is not a valid field to query", strconv.Quote(string(f)))
+		}
+		if !t.perChip {
+			continue
+		}
+		if chipCount < 0 {
+			chipCount = queryMaxChipCount()
+		}
+		if chipCount > 0 && index >= chipCount {
+			return qFields, fmt.Errorf("field %s is not a valid field to query, the boards have %d chips", strconv.Quote(string(f)), chipCount)
+		}
+	}
+	return qFields, nil
+}
+
+func getAPUInfoByBoardMapInfo(mapData map[string]string, q qField) rField {
+	val, exists := mapData[string(q)]
+	if !exists {
+		return __N_A_STR__
+	}
+	return rField(val)
+}
+
+func flatMapDataToFlatDataStringMapData(flatMapData map[string]interface{}) map[string]string {
+	m := make(map[string]string, len(flatMapData))
+	for k, v := range flatMapData {
+		switch v.(type) {
+		case string:
+			m[k] = v.(string)
+		}
+	}
+	return m
+}
+
+func writeLynSmiDetailInfo(w io.Writer, r *bufio.Reader, c io.Closer) error {
+	defer closeAndLog(c)
+	var chipCount int = 0
+	var boardIndex int = 0
+	var pciChipIndex int = -1
+	var currentBoardIndex int = 0
+	ch := make(chan []string)
+	line, err := r.ReadString(__LINE_FEED_SEP__)
+	go QueryLynPciInfo(ch)
+	pciInfoStrList := <-ch
+	err = removeDebugInfo(&line, r, err)
+	for {
+		if err != nil || io.EOF == err {
+			break
+		}
+		line = removeAndReplaceBaseInfo(line)
+		boardIndexStr, errCode := getBoardIndex(line)
+		if errCode != -1 {
+			boardIndex, _ = strconv.Atoi(boardIndexStr)
+		}
+		if !isStrBlank(line) {
+			line = replaceDriverVersionInfo(line)
+			if strings.Contains(line, __CHIP_ID_STR__) {
+				chipCount = getChipCountByChipIdAndPrint(w, r, &line)
+			}
+
+			chipIndex := computeCurrentChipIndex(boardIndex, chipCount)
+			line = replaceECIDInfoToChipIndex(w, line, chipCount, r, chipIndex)
+			computePciChipIndex(line, &currentBoardIndex, &boardIndex, &pciChipIndex)
+			line = updatePciInfo(line, pciChipIndex, chipIndex, pciInfoStrList)
+			fmt.Fprint(w, line)
+		}
+		line, err = r.ReadString(__LINE_FEED_SEP__)
+	}
+	if err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func apusInfoToFlatMap(mapData map[string]interface{}) map[string]string {
+	chip_count_str, _ := mapData[__CHIP_COUNT_KEY__].(string)
+	chip_count, _ := strconv.Atoi(chip_count_str)
+	uuid_list := getMapDataSliceValByKeyName(__UUID_LIST_KEY__, mapData)
+	chip_id_list := getMapDataSliceValByKeyName(__CHIP_ID_LIST_KEY__, mapData)
+	chip_index_list := getMapDataSliceValByKeyName(__CHIP_INDEX_LIST_KEY__, mapData)
+	util_apu_chips := getMapDataSliceValByKeyName(__UTILIZATION_APU_CHIPS_KEY__, mapData)
+	util_cpu_chips := getMapDataSliceValByKeyName(__UTILIZATION_CPU_CHIPS_KEY__, mapData)
+	util_memory_chips := getMapDataSliceValByKeyName(__UTILIZATION_MEMORY_CHIPS_KEY__, mapData)
+	util_vic_chips := getMapDataSliceValByKeyName(__UTILIZATION_VIC_CHIPS_KEY__, mapData)
+	util_ipe_chips := getMapDataSliceValByKeyName(__UTILIZATION_IPE_FPS_CHIPS_KEY__, mapData)
+	temp_current_chips := getMapDataSliceValByKeyName(__TEMPERATURE_CURRENT_CHIPS_KEY__, mapData)
+	temp_slowdown_chips := getMapDataSliceValByKeyName(__TEMPERATURE_SLOWDOWN_CHIPS_KEY__, mapData)
+	temp_shutdown_chips := getMapDataSliceValByKeyName(__TEMPERATURE_SHUTDOWN_CHIPS_KEY__, mapData)
+	voltage_current_chips := getMapDataSliceValByKeyName(__VOLTAGE_CURRENT_CHIPS_KEY__, mapData)
+	ecc_mode_current_chips := getMapDataSliceValByKeyName(__ECC_MODE_CURRENT_CHIPS_KEY__, mapData)
+	ecc_errors_corrected_chips := getMapDataSliceValByKeyName(__ECC_ERRORS_CORRECTED_TOTAL_CHIPS_KEY__, mapData)
+	ecc_errors_uncorrected_chips := getMapDataSliceValByKeyName(__ECC_ERRORS_UNCORRECTED_TOTAL_CHIPS_KEY__, mapData)
+	pci_chips := getMapDataSliceValByKeyName(__PCI_CHIPS__KEY__, mapData)
+	clocks := getMapDataMapValByKeyName(__CLOCKS_KEY__, mapData)
+	for i := 0; i < chip_count; i++ {
+		keyName := getMapDataKeyIndex(__UUID_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, uuid_list)
+		keyName = getMapDataKeyIndex(__CHIP_ID_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, chip_id_list)
+		keyName = getMapDataKeyIndex(__CHIP_INDEX_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, chip_index_list)
+		keyName = getMapDataKeyIndex(__UTILIZATION_APU_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, util_apu_chips)
+		keyName = getMapDataKeyIndex(__UTILIZATION_CPU_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, util_cpu_chips)
+		keyName = getMapDataKeyIndex(__UTILIZATION_MEMORY_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, util_memory_chips)
+		keyName = getMapDataKeyIndex(__UTILIZATION_VIC_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, util_vic_chips)
+		keyName = getMapDataKeyIndex(__UTILIZATION_IPE_FPS_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, util_ipe_chips)
+		keyName = getMapDataKeyIndex(__TEMPERATURE_CURRENT_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, temp_current_chips)
+		keyName = getMapDataKeyIndex(__TEMPERATURE_SLOWDOWN_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, temp_slowdown_chips)
+		keyName = getMapDataKeyIndex(__TEMPERATURE_SHUTDOWN_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, temp_shutdown_chips)
+		keyName = getMapDataKeyIndex(__VOLTAGE_CURRENT_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, voltage_current_chips)
+		keyName = getMapDataKeyIndex(__ECC_MODE_CURRENT_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, ecc_mode_current_chips)
+		keyName = getMapDataKeyIndex(__ECC_ERRORS_CORRECTED_TOTAL_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, ecc_errors_corrected_chips)
+		keyName = getMapDataKeyIndex(__ECC_ERRORS_UNCORRECTED_TOTAL_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataSliceIndexVal(i, ecc_errors_uncorrected_chips)
+		keyName = getMapDataKeyIndex(__PCI_VENDOR_ID_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_VENDOR_ID_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_DEVICE_ID_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_DEVICE_ID_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_SUB_VENDOR_ID_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_SUB_VENDOR_ID_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_SUB_DEVICE_ID_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_SUB_DEVICE_ID_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_BUS_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_BUS_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_DEVICE_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_DEVICE_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_SUB_FUNCTION_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_SUB_FUNCTION_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCIE_LINK_SPEED_CURRENT_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCIE_LINK_SPEED_CURRENT_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCIE_LINK_GEN_CURRENT_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCIE_LINK_GEN_CURRENT_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCIE_LINK_SPEED_MAX_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCIE_LINK_SPEED_MAX_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCIE_LINK_GEN_MAX_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCIE_LINK_GEN_MAX_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_NUMA_NODE_ID_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_NUMA_NODE_ID_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__PCI_NUMA_CPU_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataDeepMapValByKeyName(__PCI_NUMA_CPU_KEY__, i, pci_chips)
+		keyName = getMapDataKeyIndex(__CLOCKS_CURRENT_APU_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataMapSliceValByKeyName(__CLOCKS_CURRENT_APU_CHIPS_KEY__, i, clocks)
+		keyName = getMapDataKeyIndex(__CLOCKS_CURRENT_CPU_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataMapSliceValByKeyName(__CLOCKS_CURRENT_CPU_CHIPS_KEY__, i, clocks)
+		keyName = getMapDataKeyIndex(__CLOCKS_CURRENT_MEMORY_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataMapSliceValByKeyName(__CLOCKS_CURRENT_MEMORY_CHIPS_KEY__, i, clocks)
+		keyName = getMapDataKeyIndex(__CLOCKS_MAX_APU_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataMapSliceValByKeyName(__CLOCKS_MAX_APU_CHIPS_KEY__, i, clocks)
+		keyName = getMapDataKeyIndex(__CLOCKS_MAX_CPU_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataMapSliceValByKeyName(__CLOCKS_MAX_CPU_CHIPS_KEY__, i, clocks)
+		keyName = getMapDataKeyIndex(__CLOCKS_MAX_MEMORY_CHIP_KEY__, i)
+		mapData[keyName] = getMapDataMapSliceValByKeyName(__CLOCKS_MAX_MEMORY_CHIPS_KEY__, i, clocks)
+	}
+	delete(mapData, __UUID_LIST_KEY__)
+	delete(mapData, __CHIP_ID_LIST_KEY__)
+	delete(mapData, __CHIP_INDEX_LIST_KEY__)
+	delete(mapData, __UTILIZATION_APU_CHIPS_KEY__)
+	delete(mapData, __UTILIZATION_CPU_CHIPS_KEY__)
+	delete(mapData, __UTILIZATION_MEMORY_CHIPS_KEY__)
+	delete(mapData, __UTILIZATION_VIC_CHIPS_KEY__)
+	delete(mapData, __UTILIZATION_IPE_FPS_CHIPS_KEY__)
+	delete(mapData, __TEMPERATURE_CURRENT_CHIPS_KEY__)
+	delete(mapData, __TEMPERATURE_SLOWDOWN_CHIPS_KEY__)
+	delete(mapData, __TEMPERATURE_SHUTDOWN_CHIPS_KEY__)
+	delete(mapData, __VOLTAGE_CURRENT_CHIPS_KEY__)
+	delete(mapData, __ECC_MODE_CURRENT_CHIPS_KEY__)
+	delete(mapData, __ECC_ERRORS_CORRECTED_TOTAL_CHIPS_KEY__)
+	delete(mapData, __ECC_ERRORS_UNCORRECTED_TOTAL_CHIPS_KEY__)
+	delete(mapData, __PCI_CHIPS__KEY__)
+	delete(mapData, __CLOCKS_KEY__)
+	return flatMapDataToFlatDataStringMapData(mapData)
+}
+
+// addTemperatureHeadroomFields adds the temperature.headroom query fields of the chips of board to the flat map.
+func addTemperatureHeadroomFields(mapData map[string]string, board BoardMetrics) {
+	for i, chip := range board.Chips {
+		headroom := __N_A_STR__
+		if h := chip.TemperatureHeadroom(); h != nil {
+			headroom = strconv.FormatFloat(float64(*h), 'f', -1, 64)
+		}
+		mapData[getMapDataKeyIndex(__TEMPERATURE_HEADROOM_CHIP_KEY__, i)] = headroom
+	}
+}
+
+func getMapDataKeyIndex(keyName string, index int) string {
+	return keyName + strconv.Itoa(index)
+}
+
+func getMapDataSliceValByKeyName(keyName string, mapData map[string]interface{}) []interface{} {
+	sliceData, _ := mapData[keyName].([]interface{})
+	return sliceData
+}
+
+func getMapDataMapValByKeyName(keyName string, mapData map[string]interface{}) map[string]interface{} {
+	m, _ := mapData[keyName].(map[string]interface{})
+	return m
+}
+
+func getMapDataSliceIndexVal(index int, sliceData []interface{}) interface{} {
+	if index >= len(sliceData) {
+		return __N_A_STR__
+	}
+	return sliceData[index]
+}
+
+func getMapDataDeepMapValByKeyName(keyName string, index int, sliceData []interface{}) interface{} {
+	m, _ := getMapDataSliceIndexVal(index, sliceData).(map[string]interface{})
+	if m == nil {
+		return __N_A_STR__
+	}
+	return m[keyName]
+}
+
+func getMapDataMapSliceValByKeyName(keyName string, index int, mapData map[string]interface{}) interface{} {
+	return getMapDataSliceIndexVal(index, getMapDataSliceValByKeyName(keyName, mapData))
+}
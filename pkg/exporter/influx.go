@@ -0,0 +1,208 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	InfluxMeasurement       = __METRIC_NAMESPACE__
+	DefaultInfluxBatch      = 10
+	__INFLUX_TIMEOUT__      = 10 * time.Second
+	__INFLUX_MAX_BATCHES__  = 10
+	__INFLUX_CONTENT_TYPE__ = "text/plain; charset=utf-8"
+)
+
+var influxTagReplacer = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, __LINE_FEED_STR__, `\n`)
+
+type influxField struct {
+	name string
+	val  interface{}
+}
+
+// influxFieldValue formats a typed value, counters and widths as integers, nil values reported as N/A are skipped.
+func influxFieldValue(val interface{}) (string, bool) {
+	switch v := val.(type) {
+	case int:
+		return strconv.Itoa(v) + "i", true
+	case *uint64:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatUint(*v, 10) + "i", true
+	case *bool:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatBool(*v), true
+	}
+	v, ok := metricValue(val)
+	if !ok {
+		return "", false
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64), true
+}
+
+// writeInfluxLine writes a line of the measurement, tags with an empty value are left out and a line without field is
+// not written.
+func writeInfluxLine(w io.Writer, tags []metricLabel, fields []influxField, timestamp time.Time) error {
+	var fieldStrs []string
+	for _, f := range fields {
+		if v, ok := influxFieldValue(f.val); ok {
+			fieldStrs = append(fieldStrs, f.name+"="+v)
+		}
+	}
+	if len(fieldStrs) == 0 {
+		return nil
+	}
+	line := InfluxMeasurement
+	for _, t := range tags {
+		if t.value != "" && !strings.Contains(t.value, __N_A_STR__) {
+			line += __COMMA_SEP__ + t.name + "=" + influxTagReplacer.Replace(t.value)
+		}
+	}
+	line += __SPCAE_SEP__ + strings.Join(fieldStrs, __COMMA_SEP__)
+	if !timestamp.IsZero() {
+		line += __SPCAE_SEP__ + strconv.FormatInt(timestamp.UnixNano(), 10)
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
+
+// WriteInflux writes the boards as InfluxDB line protocol, a line per board tagged with its index and serial number and a
+// line per chip also tagged with the chip index and UUID, at the time of the sample.
+func WriteInflux(w io.Writer, boards []BoardMetrics) error {
+	for _, b := range boards {
+		tags := []metricLabel{{"board_index", strconv.Itoa(b.BoardIndex)}, {"serial_number", b.SerialNumber}}
+		fields := []influxField{
+			{"chip_count", b.ChipCount},
+			{"utilization_apu", b.Utilization.Apu},
+			{"utilization_cpu", b.Utilization.Cpu},
+			{"utilization_vic", b.Utilization.Vic},
+			{"utilization_memory", b.Utilization.Memory},
+			{"ipe_fps", b.IpeFps},
+			{"fan_speed", b.FanSpeed},
+			{"voltage_input", b.VoltageInput},
+			{"power_draw", b.PowerDraw},
+			{"power_limit", b.PowerLimit},
+			{"ecc_errors_corrected", b.EccErrorsCorrected},
+			{"ecc_errors_uncorrected", b.EccErrorsUncorrected},
+		}
+		if err := writeInfluxLine(w, tags, fields, b.Timestamp); err != nil {
+			return err
+		}
+		for _, c := range b.Chips {
+			tags := []metricLabel{
+				{"board_index", strconv.Itoa(b.BoardIndex)},
+				{"chip_index", strconv.Itoa(c.ChipIndex)},
+				{"serial_number", b.SerialNumber},
+				{"uuid", c.Uuid},
+			}
+			fields := []influxField{
+				{"utilization_apu", c.Utilization.Apu},
+				{"utilization_cpu", c.Utilization.Cpu},
+				{"utilization_vic", c.Utilization.Vic},
+				{"utilization_memory", c.Utilization.Memory},
+				{"ipe_fps", c.IpeFps},
+				{"temperature", c.Temperature},
+				{"temperature_slowdown", c.TemperatureSlowdown},
+				{"temperature_shutdown", c.TemperatureShutdown},
+				{"temperature_headroom", c.TemperatureHeadroom()},
+				{"voltage", c.Voltage},
+				{"clock_apu", c.Clocks.Apu},
+				{"clock_apu_max", c.Clocks.ApuMax},
+				{"clock_cpu", c.Clocks.Cpu},
+				{"clock_cpu_max", c.Clocks.CpuMax},
+				{"clock_memory", c.Clocks.Memory},
+				{"clock_memory_max", c.Clocks.MemoryMax},
+				{"clocks_throttled", c.ThrottleReasons.Active},
+				{"ecc_mode_enabled", c.EccEnabled()},
+				{"ecc_errors_corrected", c.EccErrorsCorrected},
+				{"ecc_errors_uncorrected", c.EccErrorsUncorrected},
+				{"pcie_link_speed_current", c.Pci.LinkSpeedCurrent},
+				{"pcie_link_speed_max", c.Pci.LinkSpeedMax},
+				{"pcie_link_width_current", c.Pci.LinkWidthCurrent},
+				{"pcie_link_width_max", c.Pci.LinkWidthMax},
+			}
+			if err := writeInfluxLine(w, tags, fields, b.Timestamp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// InfluxWriter posts the samples to the write endpoint of InfluxDB, e.g. http://influxdb:8086/api/v2/write?bucket=apu
+// or http://influxdb:8086/write?db=apu, once Batch samples are buffered. Samples that could not be posted are sent
+// again with the next batch, up to 10 batches.
+type InfluxWriter struct {
+	Url     string
+	Token   string
+	Batch   int
+	client  *http.Client
+	buf     bytes.Buffer
+	samples int
+}
+
+func NewInfluxWriter(url string, token string, batch int) *InfluxWriter {
+	if batch <= 0 {
+		batch = DefaultInfluxBatch
+	}
+	return &InfluxWriter{Url: url, Token: token, Batch: batch, client: &http.Client{Timeout: __INFLUX_TIMEOUT__}}
+}
+
+// Add buffers a sample of the boards and posts the buffered samples when the batch is full.
+func (iw *InfluxWriter) Add(boards []BoardMetrics) error {
+	if err := WriteInflux(&iw.buf, boards); err != nil {
+		return err
+	}
+	iw.samples++
+	if iw.samples < iw.Batch {
+		return nil
+	}
+	return iw.Flush()
+}
+
+// Flush posts the buffered samples, they are dropped when the post keeps failing for 10 batches.
+func (iw *InfluxWriter) Flush() error {
+	if iw.buf.Len() == 0 {
+		return nil
+	}
+	err := iw.post(iw.buf.Bytes())
+	if err != nil && iw.samples < iw.Batch*__INFLUX_MAX_BATCHES__ {
+		return err
+	}
+	if err != nil {
+		err = fmt.Errorf("%v, %d samples dropped", err, iw.samples)
+	}
+	iw.buf.Reset()
+	iw.samples = 0
+	return err
+}
+
+func (iw *InfluxWriter) post(data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, iw.Url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", __INFLUX_CONTENT_TYPE__)
+	if iw.Token != "" {
+		req.Header.Set("Authorization", "Token "+iw.Token)
+	}
+	resp, err := iw.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("InfluxDB write failed: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
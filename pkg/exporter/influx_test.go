@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func influxTestBoards() []BoardMetrics {
+	watts := func(v Watts) *Watts { return &v }
+	counter := func(v uint64) *uint64 { return &v }
+	active := true
+	return []BoardMetrics{{
+		Timestamp:          time.Unix(1700000000, 123000000),
+		BoardIndex:         0,
+		SerialNumber:       "2203A 0012",
+		ChipCount:          1,
+		PowerDraw:          watts(31.5),
+		EccErrorsCorrected: counter(2),
+		Chips: []ChipMetrics{{
+			ChipIndex:           1,
+			Uuid:                "1e9f27c5-4c59-4e58-0000-000000000001",
+			Temperature:         celsius(97),
+			TemperatureSlowdown: celsius(95),
+			Clocks:              ChipClocks{Apu: mhz(800)},
+			ThrottleReasons:     ThrottleReasons{Active: &active},
+		}},
+	}}
+}
+
+func TestWriteInflux(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteInflux(&sb, influxTestBoards()); err != nil {
+		t.Fatal(err)
+	}
+	want := `lynxi_apu,board_index=0,serial_number=2203A\ 0012 chip_count=1i,power_draw=31.5,ecc_errors_corrected=2i 1700000000123000000
+lynxi_apu,board_index=0,chip_index=1,serial_number=2203A\ 0012,uuid=1e9f27c5-4c59-4e58-0000-000000000001 temperature=97,temperature_slowdown=95,temperature_headroom=-2,clock_apu=800,clocks_throttled=true 1700000000123000000
+`
+	if got := sb.String(); got != want {
+		t.Errorf("WriteInflux =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInfluxWriter(t *testing.T) {
+	var posts []string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Query().Get("bucket") != "apu" || r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		posts = append(posts, string(body))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	iw := NewInfluxWriter(server.URL+"/api/v2/write?bucket=apu", "secret", 2)
+	boards := influxTestBoards()
+	for i := 0; i < 3; i++ {
+		if err := iw.Add(boards); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(posts) != 1 || strings.Count(posts[0], "\n") != 4 {
+		t.Fatalf("posts after 3 samples = %q, want a batch of 2 samples", posts)
+	}
+	if err := iw.Flush(); err != nil || len(posts) != 2 || strings.Count(posts[1], "\n") != 2 {
+		t.Fatalf("Flush = %v, posts = %q", err, posts)
+	}
+
+	status = http.StatusInternalServerError
+	for i := 0; i < 2; i++ {
+		if err := iw.Add(boards); err != nil && i == 0 {
+			t.Fatal(err)
+		}
+	}
+	status = http.StatusNoContent
+	if err := iw.Flush(); err != nil || strings.Count(posts[len(posts)-1], "\n") != 4 {
+		t.Errorf("samples of the failed post not sent again: %v, %q", err, posts)
+	}
+}
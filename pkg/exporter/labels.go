@@ -0,0 +1,94 @@
+package exporter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Node labels of the node-feature-discovery local source, named after the labels of the NVIDIA GPU feature discovery.
+const (
+	LabelPrefix          = "lynxi.com/"
+	LabelCount           = LabelPrefix + "apu.count"
+	LabelProduct         = LabelPrefix + "apu.product"
+	LabelDriverVersion   = LabelPrefix + "apu.driver-version"
+	LabelFirmwareVersion = LabelPrefix + "apu.firmware-version"
+	LabelChipsPerBoard   = LabelPrefix + "apu.chips-per-board"
+	LabelPcieGen         = LabelPrefix + "apu.pcie-gen"
+	__LABEL_VALUE_MAX__  = 63
+)
+
+var invalidLabelValueRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// pcieGenSpeeds are the transfer rates of the PCIe generations 1 to 6 in GT/s.
+var pcieGenSpeeds = []GTps{2.5, 5, 8, 16, 32, 64}
+
+// QueryNodeLabels returns the labels of the node, only the driver version and a count of 0 without board.
+func QueryNodeLabels() (map[string]string, error) {
+	boards, err := QueryBoards()
+	if err != nil {
+		return nil, err
+	}
+	return NodeLabels(boards, strings.TrimPrefix(getVersion(Driver), VersionShortStr)), nil
+}
+
+// NodeLabels derives the labels of the boards, the product, firmware, chips per board and PCIe generation of the first
+// board. Values reported as N/A are left out.
+func NodeLabels(boards []BoardBaseInfo, driverVersion string) map[string]string {
+	labels := make(map[string]string)
+	addLabel := func(name string, val string) {
+		if val = labelValue(val); val != "" {
+			labels[name] = val
+		}
+	}
+	chips := 0
+	for _, b := range boards {
+		count, _ := strconv.Atoi(b.ChipCount)
+		chips += count
+	}
+	labels[LabelCount] = strconv.Itoa(chips)
+	if driverVersion != __UNKNOWN_STR__ {
+		addLabel(LabelDriverVersion, driverVersion)
+	}
+	if len(boards) == 0 {
+		return labels
+	}
+	b := boards[0]
+	addLabel(LabelProduct, b.ProductName)
+	addLabel(LabelFirmwareVersion, b.FirmwareVersion)
+	addLabel(LabelChipsPerBoard, b.ChipCount)
+	if len(b.PciInfoList) > 0 {
+		if gen := pcieGen(parseGTps(b.PciInfoList[0].MaxSpeed)); gen > 0 {
+			labels[LabelPcieGen] = strconv.Itoa(gen)
+		}
+	}
+	return labels
+}
+
+// pcieGen returns the PCIe generation of a link speed, 0 when the speed is not reported.
+func pcieGen(speed *GTps) int {
+	if speed == nil || *speed <= 0 {
+		return 0
+	}
+	gen := 1
+	for i, s := range pcieGenSpeeds {
+		if *speed >= s {
+			gen = i + 1
+		}
+	}
+	return gen
+}
+
+// labelValue makes val a valid label value: alphanumerics, '-', '_' and '.', starting and ending with an alphanumeric,
+// at most 63 characters. Other characters are replaced with '-', e.g. "HP 300" is "HP-300".
+func labelValue(val string) string {
+	val = strings.TrimSpace(val)
+	if val == "" || strings.Contains(val, __N_A_STR__) {
+		return ""
+	}
+	val = invalidLabelValueRegexp.ReplaceAllString(val, "-")
+	if len(val) > __LABEL_VALUE_MAX__ {
+		val = val[:__LABEL_VALUE_MAX__]
+	}
+	return strings.Trim(val, "._-")
+}
@@ -0,0 +1,50 @@
+package exporter
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestQueryNodeLabels(t *testing.T) {
+	withReplaySource(t, filepath.Join("testdata", "multi_board"), func() {
+		labels, err := QueryNodeLabels()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{
+			LabelCount:           "6",
+			LabelProduct:         "HP300",
+			LabelDriverVersion:   "1.6.0",
+			LabelFirmwareVersion: "2.1.0",
+			LabelChipsPerBoard:   "3",
+			LabelPcieGen:         "3",
+		}
+		if !reflect.DeepEqual(labels, want) {
+			t.Errorf("QueryNodeLabels = %v, want %v", labels, want)
+		}
+	})
+}
+
+func TestLabelValue(t *testing.T) {
+	cases := []struct {
+		val, want string
+	}{
+		{"HP300", "HP300"},
+		{" HP 300 (rev 2) ", "HP-300-rev-2"},
+		{"V1.6.0_rc1", "V1.6.0_rc1"},
+		{__N_A_STR__, ""},
+		{"-x-", "x"},
+	}
+	for _, c := range cases {
+		if got := labelValue(c.val); got != c.want {
+			t.Errorf("labelValue(%q) = %q, want %q", c.val, got, c.want)
+		}
+	}
+	for speed, gen := range map[GTps]int{2.5: 1, 8: 3, 16: 4, 32: 5} {
+		s := speed
+		if got := pcieGen(&s); got != gen {
+			t.Errorf("pcieGen(%g) = %d, want %d", speed, got, gen)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultListenAddress = ":9842"
+	DefaultMetricsPath   = "/metrics"
+)
+
+const (
+	__METRIC_NAMESPACE__ = "lynxi_apu"
+	__GAUGE_TYPE__       = "gauge"
+	__COUNTER_TYPE__     = "counter"
+	__ENABLED_STR__      = "Enabled"
+)
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, __LINE_FEED_STR__, `\n`)
+
+type metricLabel struct {
+	name  string
+	value string
+}
+
+type metricSample struct {
+	labels []metricLabel
+	value  float64
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string
+	samples []metricSample
+}
+
+func newMetricFamily(name string, typ string, help string) *metricFamily {
+	return &metricFamily{name: __METRIC_NAMESPACE__ + "_" + name, help: help, typ: typ}
+}
+
+// add records a sample of a typed value, nil values reported as N/A are skipped.
+func (mf *metricFamily) add(val interface{}, labels ...metricLabel) {
+	v, ok := metricValue(val)
+	if !ok {
+		return
+	}
+	mf.samples = append(mf.samples, metricSample{labels: labels, value: v})
+}
+
+func (mf *metricFamily) write(w io.Writer) {
+	if len(mf.samples) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n", mf.name, mf.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", mf.name, mf.typ)
+	for _, s := range mf.samples {
+		fmt.Fprint(w, mf.name)
+		if len(s.labels) > 0 {
+			labels := make([]string, len(s.labels))
+			for i, l := range s.labels {
+				labels[i] = l.name + "=\"" + labelValueReplacer.Replace(l.value) + "\""
+			}
+			fmt.Fprint(w, "{"+strings.Join(labels, __COMMA_SEP__)+"}")
+		}
+		fmt.Fprintln(w, __SPCAE_SEP__+strconv.FormatFloat(s.value, 'g', -1, 64))
+	}
+}
+
+func metricValue(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case *float64:
+		if v != nil {
+			return *v, true
+		}
+	case *Percent:
+		if v != nil {
+			return float64(*v), true
+		}
+	case *Celsius:
+		if v != nil {
+			return float64(*v), true
+		}
+	case *Watts:
+		if v != nil {
+			return float64(*v), true
+		}
+	case *Volts:
+		if v != nil {
+			return float64(*v), true
+		}
+	case *MHz:
+		if v != nil {
+			return float64(*v), true
+		}
+	case *GTps:
+		if v != nil {
+			return float64(*v), true
+		}
+	case *uint64:
+		if v != nil {
+			return float64(*v), true
+		}
+	case *bool:
+		if v != nil && *v {
+			return 1, true
+		} else if v != nil {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+func getListVal(list []string, index int) string {
+	if index < len(list) {
+		return list[index]
+	}
+	return ""
+}
+
+func boardMetricLabels(board BoardMetrics, extra ...metricLabel) []metricLabel {
+	labels := []metricLabel{
+		{"board_index", strconv.Itoa(board.BoardIndex)},
+		{"serial_number", board.SerialNumber},
+	}
+	return append(labels, extra...)
+}
+
+func chipMetricLabels(board BoardMetrics, chip ChipMetrics, extra ...metricLabel) []metricLabel {
+	labels := []metricLabel{
+		{"board_index", strconv.Itoa(board.BoardIndex)},
+		{"chip_index", strconv.Itoa(chip.ChipIndex)},
+		{"uuid", chip.Uuid},
+		{"serial_number", board.SerialNumber},
+	}
+	return append(labels, extra...)
+}
+
+func collectMetricFamilies(boardMetricsList []BoardMetrics) []*metricFamily {
+	var (
+		boardInfo         = newMetricFamily("board_info", __GAUGE_TYPE__, "Static information about the APU board, value is always 1.")
+		boardChipCount    = newMetricFamily("board_chip_count", __GAUGE_TYPE__, "Number of chips on the APU board.")
+		boardUtil         = newMetricFamily("board_utilization_percent", __GAUGE_TYPE__, "Board utilization per engine, in %.")
+		boardIpeFps       = newMetricFamily("board_ipe_fps", __GAUGE_TYPE__, "Board IPE throughput, in frames per second.")
+		fanSpeed          = newMetricFamily("fan_speed_percent", __GAUGE_TYPE__, "Fan speed, in %.")
+		voltageInput      = newMetricFamily("board_input_voltage_volts", __GAUGE_TYPE__, "Board input voltage, in volts.")
+		powerDraw         = newMetricFamily("power_draw_watts", __GAUGE_TYPE__, "The last measured power draw for the entire board, in watts.")
+		powerLimit        = newMetricFamily("power_limit_watts", __GAUGE_TYPE__, "The software power limit of the board, in watts.")
+		boardEccCorrected = newMetricFamily("board_ecc_errors_corrected_total", __COUNTER_TYPE__, "Corrected DDR ECC errors detected on the board.")
+		boardEccUncorrect = newMetricFamily("board_ecc_errors_uncorrected_total", __COUNTER_TYPE__, "Uncorrected DDR ECC errors detected on the board.")
+		chipInfo          = newMetricFamily("chip_info", __GAUGE_TYPE__, "Static information about the APU chip, value is always 1.")
+		chipUtil          = newMetricFamily("utilization_percent", __GAUGE_TYPE__, "Chip utilization per engine, in %.")
+		chipIpeFps        = newMetricFamily("ipe_fps", __GAUGE_TYPE__, "Chip IPE throughput, in frames per second.")
+		temperature       = newMetricFamily("temperature_celsius", __GAUGE_TYPE__, "Core chip temperature, in degrees C.")
+		tempSlowdown      = newMetricFamily("temperature_slowdown_celsius", __GAUGE_TYPE__, "BIU temperature at which the chip slows down, in degrees C.")
+		tempShutdown      = newMetricFamily("temperature_shutdown_celsius", __GAUGE_TYPE__, "BIU temperature at which the chip shuts down, in degrees C.")
+		tempHeadroom      = newMetricFamily("temperature_headroom_celsius", __GAUGE_TYPE__, "Degrees C left before the chip reaches its slowdown temperature.")
+		voltage           = newMetricFamily("voltage_volts", __GAUGE_TYPE__, "Chip voltage, in volts.")
+		clock             = newMetricFamily("clock_mhz", __GAUGE_TYPE__, "Current chip clock frequency, in MHz.")
+		clockMax          = newMetricFamily("clock_max_mhz", __GAUGE_TYPE__, "Maximum chip clock frequency, in MHz.")
+		throttled         = newMetricFamily("clocks_throttled", __GAUGE_TYPE__, "Whether a clock of the chip is below its maximum.")
+		throttleReason    = newMetricFamily("clocks_throttle_reason_active", __GAUGE_TYPE__, "Whether the clocks of the chip are reduced for the reason.")
+		eccMode           = newMetricFamily("ecc_mode_enabled", __GAUGE_TYPE__, "Whether ECC is enabled on the chip.")
+		eccCorrected      = newMetricFamily("ecc_errors_corrected_total", __COUNTER_TYPE__, "Corrected DDR ECC errors detected on the chip.")
+		eccUncorrected    = newMetricFamily("ecc_errors_uncorrected_total", __COUNTER_TYPE__, "Uncorrected DDR ECC errors detected on the chip.")
+		pcieSpeedCurrent  = newMetricFamily("pcie_link_speed_current_gts", __GAUGE_TYPE__, "Current PCIe link speed, in GT/s.")
+		pcieSpeedMax      = newMetricFamily("pcie_link_speed_max_gts", __GAUGE_TYPE__, "Maximum PCIe link speed, in GT/s.")
+		pcieWidthCurrent  = newMetricFamily("pcie_link_width_current", __GAUGE_TYPE__, "Current PCIe link width, in lanes.")
+		pcieWidthMax      = newMetricFamily("pcie_link_width_max", __GAUGE_TYPE__, "Maximum PCIe link width, in lanes.")
+		utilEngineLabel   = "engine"
+		clockTypeLabel    = "clock"
+		reasonLabel       = "reason"
+		apuLabelVal       = strings.ToLower(__APU_STR__)
+		cpuLabelVal       = strings.ToLower(__CPU_STR__)
+		vicLabelVal       = strings.ToLower(__VIC_STR__)
+		memoryLabelVal    = strings.ToLower(__MEMORY_STR__)
+		infoVal           = 1.0
+	)
+	for _, board := range boardMetricsList {
+		boardInfo.add(infoVal, boardMetricLabels(board,
+			metricLabel{"name", board.ProductName},
+			metricLabel{"product_brand", board.ProductBrand},
+			metricLabel{"product_number", board.ProductNumber},
+			metricLabel{"driver_version", board.DriverVersion},
+			metricLabel{"firmware_version", board.FirmwareVersion})...)
+		boardChipCount.add(board.ChipCount, boardMetricLabels(board)...)
+		boardUtil.add(board.Utilization.Apu, boardMetricLabels(board, metricLabel{utilEngineLabel, apuLabelVal})...)
+		boardUtil.add(board.Utilization.Cpu, boardMetricLabels(board, metricLabel{utilEngineLabel, cpuLabelVal})...)
+		boardUtil.add(board.Utilization.Vic, boardMetricLabels(board, metricLabel{utilEngineLabel, vicLabelVal})...)
+		boardUtil.add(board.Utilization.Memory, boardMetricLabels(board, metricLabel{utilEngineLabel, memoryLabelVal})...)
+		boardIpeFps.add(board.IpeFps, boardMetricLabels(board)...)
+		fanSpeed.add(board.FanSpeed, boardMetricLabels(board)...)
+		voltageInput.add(board.VoltageInput, boardMetricLabels(board)...)
+		powerDraw.add(board.PowerDraw, boardMetricLabels(board)...)
+		powerLimit.add(board.PowerLimit, boardMetricLabels(board)...)
+		boardEccCorrected.add(board.EccErrorsCorrected, boardMetricLabels(board)...)
+		boardEccUncorrect.add(board.EccErrorsUncorrected, boardMetricLabels(board)...)
+
+		for _, chip := range board.Chips {
+			numaNode := ""
+			if chip.Pci.NumaNode != nil {
+				numaNode = strconv.Itoa(*chip.Pci.NumaNode)
+			}
+			chipInfo.add(infoVal, chipMetricLabels(board, chip,
+				metricLabel{"chip_id", chip.ChipId},
+				metricLabel{"pci_bus_id", chip.Pci.BusId},
+				metricLabel{"numa_node", numaNode})...)
+			chipUtil.add(chip.Utilization.Apu, chipMetricLabels(board, chip, metricLabel{utilEngineLabel, apuLabelVal})...)
+			chipUtil.add(chip.Utilization.Cpu, chipMetricLabels(board, chip, metricLabel{utilEngineLabel, cpuLabelVal})...)
+			chipUtil.add(chip.Utilization.Vic, chipMetricLabels(board, chip, metricLabel{utilEngineLabel, vicLabelVal})...)
+			chipUtil.add(chip.Utilization.Memory, chipMetricLabels(board, chip, metricLabel{utilEngineLabel, memoryLabelVal})...)
+			chipIpeFps.add(chip.IpeFps, chipMetricLabels(board, chip)...)
+			temperature.add(chip.Temperature, chipMetricLabels(board, chip)...)
+			tempSlowdown.add(chip.TemperatureSlowdown, chipMetricLabels(board, chip)...)
+			tempShutdown.add(chip.TemperatureShutdown, chipMetricLabels(board, chip)...)
+			tempHeadroom.add(chip.TemperatureHeadroom(), chipMetricLabels(board, chip)...)
+			voltage.add(chip.Voltage, chipMetricLabels(board, chip)...)
+			clock.add(chip.Clocks.Apu, chipMetricLabels(board, chip, metricLabel{clockTypeLabel, apuLabelVal})...)
+			clock.add(chip.Clocks.Cpu, chipMetricLabels(board, chip, metricLabel{clockTypeLabel, cpuLabelVal})...)
+			clock.add(chip.Clocks.Memory, chipMetricLabels(board, chip, metricLabel{clockTypeLabel, memoryLabelVal})...)
+			clockMax.add(chip.Clocks.ApuMax, chipMetricLabels(board, chip, metricLabel{clockTypeLabel, apuLabelVal})...)
+			clockMax.add(chip.Clocks.CpuMax, chipMetricLabels(board, chip, metricLabel{clockTypeLabel, cpuLabelVal})...)
+			clockMax.add(chip.Clocks.MemoryMax, chipMetricLabels(board, chip, metricLabel{clockTypeLabel, memoryLabelVal})...)
+			throttled.add(chip.ThrottleReasons.Active, chipMetricLabels(board, chip)...)
+			throttleReason.add(chip.ThrottleReasons.Thermal, chipMetricLabels(board, chip, metricLabel{reasonLabel, "thermal"})...)
+			throttleReason.add(chip.ThrottleReasons.PowerCap, chipMetricLabels(board, chip, metricLabel{reasonLabel, "power_cap"})...)
+			throttleReason.add(chip.ThrottleReasons.Idle, chipMetricLabels(board, chip, metricLabel{reasonLabel, "idle"})...)
+			throttleReason.add(chip.ThrottleReasons.Unknown, chipMetricLabels(board, chip, metricLabel{reasonLabel, "unknown"})...)
+			eccMode.add(chip.EccEnabled(), chipMetricLabels(board, chip)...)
+			eccCorrected.add(chip.EccErrorsCorrected, chipMetricLabels(board, chip)...)
+			eccUncorrected.add(chip.EccErrorsUncorrected, chipMetricLabels(board, chip)...)
+			pcieSpeedCurrent.add(chip.Pci.LinkSpeedCurrent, chipMetricLabels(board, chip)...)
+			pcieSpeedMax.add(chip.Pci.LinkSpeedMax, chipMetricLabels(board, chip)...)
+			pcieWidthCurrent.add(chip.Pci.LinkWidthCurrent, chipMetricLabels(board, chip)...)
+			pcieWidthMax.add(chip.Pci.LinkWidthMax, chipMetricLabels(board, chip)...)
+		}
+	}
+	return []*metricFamily{
+		boardInfo, boardChipCount, boardUtil, boardIpeFps, fanSpeed, voltageInput,
+		powerDraw, powerLimit, boardEccCorrected, boardEccUncorrect,
+		chipInfo, chipUtil, chipIpeFps, temperature, tempSlowdown, tempShutdown, tempHeadroom, voltage, clock, clockMax,
+		throttled, throttleReason, eccMode, eccCorrected, eccUncorrected,
+		pcieSpeedCurrent, pcieSpeedMax, pcieWidthCurrent, pcieWidthMax,
+	}
+}
+
+func pciBusId(info BoardPciDeviceInfo) string {
+	if info.BusNum == "" {
+		return ""
+	}
+	return "0000" + __COLON_SEP__ + info.BusNum + __COLON_SEP__ + info.Device + __DOT_SEP__ + info.Function
+}
+
+func WriteMetrics(w io.Writer) error {
+	start := time.Now()
+	up := newMetricFamily("up", __GAUGE_TYPE__, "Whether the last query of lynxi-smi was successful.")
+	duration := newMetricFamily("scrape_duration_seconds", __GAUGE_TYPE__, "Duration of the last query of lynxi-smi, in seconds.")
+	boardMetricsList, err := QueryBoardMetrics()
+	if err != nil {
+		log.Errorln(err)
+		up.add(0.0)
+	} else {
+		up.add(1.0)
+		for _, mf := range collectMetricFamilies(boardMetricsList) {
+			mf.write(w)
+		}
+	}
+	duration.add(time.Since(start).Seconds())
+	up.write(w)
+	duration.write(w)
+	return err
+}
+
+func MetricsHandler() http.Handler {
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var buf bytes.Buffer
+		_ = WriteMetrics(&buf)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			log.Debugln(err)
+		}
+	})
+}
+
+func ServeMetrics(listenAddress string, metricsPath string) error {
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, MetricsHandler())
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "<html><head><title>Lynxi APU Exporter</title></head><body><h1>Lynxi APU Exporter</h1><p><a href=%q>Metrics</a></p></body></html>\n", metricsPath)
+	})
+	log.Infof("Listening on %s, metrics path %s", listenAddress, metricsPath)
+	return http.ListenAndServe(listenAddress, mux)
+}
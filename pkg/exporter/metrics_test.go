@@ -0,0 +1,46 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetricFamiliesExposition(t *testing.T) {
+	watts := Watts(31.5)
+	boards := []BoardMetrics{{
+		BoardIndex:   0,
+		SerialNumber: "2203A0012",
+		ProductName:  "HP300 \"rev\\2\"\nB",
+		ChipCount:    2,
+		PowerDraw:    &watts,
+		Chips: []ChipMetrics{
+			{ChipIndex: 0, Uuid: "uuid-0", Temperature: celsius(45)},
+			{ChipIndex: 1, Uuid: "uuid-1", Temperature: celsius(46.5)},
+		},
+	}}
+	var sb strings.Builder
+	for _, mf := range collectMetricFamilies(boards) {
+		mf.write(&sb)
+	}
+	want := `# HELP lynxi_apu_board_info Static information about the APU board, value is always 1.
+# TYPE lynxi_apu_board_info gauge
+lynxi_apu_board_info{board_index="0",serial_number="2203A0012",name="HP300 \"rev\\2\"\nB",product_brand="",product_number="",driver_version="",firmware_version=""} 1
+# HELP lynxi_apu_board_chip_count Number of chips on the APU board.
+# TYPE lynxi_apu_board_chip_count gauge
+lynxi_apu_board_chip_count{board_index="0",serial_number="2203A0012"} 2
+# HELP lynxi_apu_power_draw_watts The last measured power draw for the entire board, in watts.
+# TYPE lynxi_apu_power_draw_watts gauge
+lynxi_apu_power_draw_watts{board_index="0",serial_number="2203A0012"} 31.5
+# HELP lynxi_apu_chip_info Static information about the APU chip, value is always 1.
+# TYPE lynxi_apu_chip_info gauge
+lynxi_apu_chip_info{board_index="0",chip_index="0",uuid="uuid-0",serial_number="2203A0012",chip_id="",pci_bus_id="",numa_node=""} 1
+lynxi_apu_chip_info{board_index="0",chip_index="1",uuid="uuid-1",serial_number="2203A0012",chip_id="",pci_bus_id="",numa_node=""} 1
+# HELP lynxi_apu_temperature_celsius Core chip temperature, in degrees C.
+# TYPE lynxi_apu_temperature_celsius gauge
+lynxi_apu_temperature_celsius{board_index="0",chip_index="0",uuid="uuid-0",serial_number="2203A0012"} 45
+lynxi_apu_temperature_celsius{board_index="0",chip_index="1",uuid="uuid-1",serial_number="2203A0012"} 46.5
+`
+	if got := sb.String(); got != want {
+		t.Errorf("exposition =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,337 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// __NANO_DIGITS__ is the number of fraction digits of a timestamp in nanoseconds.
+const __NANO_DIGITS__ = 9
+
+// Unit types of the typed metrics model, nil pointers stand for values reported as N/A.
+type (
+	Percent float64
+	Celsius float64
+	Watts   float64
+	Volts   float64
+	MHz     float64
+	GTps    float64
+)
+
+type BoardMetrics struct {
+	Timestamp            time.Time     `json:"timestamp"`
+	BoardIndex           int           `json:"board_index"`
+	ProductName          string        `json:"name"`
+	ProductBrand         string        `json:"product_brand"`
+	ProductNumber        string        `json:"product_number"`
+	DriverVersion        string        `json:"driver_version"`
+	FirmwareVersion      string        `json:"firmware_version"`
+	SerialNumber         string        `json:"serial_number"`
+	ChipCount            int           `json:"chip_count"`
+	Utilization          Utilization   `json:"utilization"`
+	IpeFps               *float64      `json:"ipe_fps"`
+	FanSpeed             *Percent      `json:"fan_speed"`
+	VoltageInput         *Volts        `json:"voltage_input"`
+	PowerDraw            *Watts        `json:"power_draw"`
+	PowerLimit           *Watts        `json:"power_limit"`
+	EccErrorsCorrected   *uint64       `json:"ecc_errors_corrected"`
+	EccErrorsUncorrected *uint64       `json:"ecc_errors_uncorrected"`
+	Chips                []ChipMetrics `json:"chips"`
+}
+
+type ChipMetrics struct {
+	ChipIndex            int             `json:"chip_index"`
+	ChipId               string          `json:"chip_id"`
+	Uuid                 string          `json:"uuid"`
+	Utilization          Utilization     `json:"utilization"`
+	IpeFps               *float64        `json:"ipe_fps"`
+	Temperature          *Celsius        `json:"temperature"`
+	TemperatureSlowdown  *Celsius        `json:"temperature_slowdown"`
+	TemperatureShutdown  *Celsius        `json:"temperature_shutdown"`
+	Voltage              *Volts          `json:"voltage"`
+	Clocks               ChipClocks      `json:"clocks"`
+	ThrottleReasons      ThrottleReasons `json:"clocks_throttle_reasons"`
+	EccMode              string          `json:"ecc_mode"`
+	EccErrorsCorrected   *uint64         `json:"ecc_errors_corrected"`
+	EccErrorsUncorrected *uint64         `json:"ecc_errors_uncorrected"`
+	Pci                  ChipPci         `json:"pci"`
+}
+
+type Utilization struct {
+	Apu    *Percent `json:"apu"`
+	Cpu    *Percent `json:"cpu"`
+	Vic    *Percent `json:"vic"`
+	Memory *Percent `json:"memory"`
+}
+
+type ChipClocks struct {
+	Apu       *MHz `json:"apu"`
+	ApuMax    *MHz `json:"apu_max"`
+	Cpu       *MHz `json:"cpu"`
+	CpuMax    *MHz `json:"cpu_max"`
+	Memory    *MHz `json:"memory"`
+	MemoryMax *MHz `json:"memory_max"`
+}
+
+type ChipPci struct {
+	BusId            string  `json:"bus_id"`
+	VendorId         string  `json:"vendor_id"`
+	DeviceId         string  `json:"device_id"`
+	SubVendorId      string  `json:"sub_vendor_id"`
+	SubDeviceId      string  `json:"sub_device_id"`
+	LinkSpeedCurrent *GTps   `json:"link_speed_current"`
+	LinkSpeedMax     *GTps   `json:"link_speed_max"`
+	LinkWidthCurrent *uint64 `json:"link_width_current"`
+	LinkWidthMax     *uint64 `json:"link_width_max"`
+	NumaNode         *int    `json:"numa_node"`
+	NumaCPUList      string  `json:"numa_cpu_list"`
+}
+
+func QueryBoardMetrics() ([]BoardMetrics, error) {
+	boardBaseInfoList, err := QueryBoards()
+	if err != nil {
+		return nil, err
+	}
+	return NewBoardMetricsList(boardBaseInfoList), nil
+}
+
+func NewBoardMetricsList(boardBaseInfoList []BoardBaseInfo) []BoardMetrics {
+	boardMetricsList := make([]BoardMetrics, len(boardBaseInfoList))
+	for i, info := range boardBaseInfoList {
+		boardMetricsList[i] = NewBoardMetrics(info)
+	}
+	return boardMetricsList
+}
+
+func NewBoardMetrics(info BoardBaseInfo) BoardMetrics {
+	m := BoardMetrics{
+		ProductName:     info.ProductName,
+		ProductBrand:    info.ProductBrand,
+		ProductNumber:   info.ProductNumber,
+		DriverVersion:   info.DriverVersion,
+		FirmwareVersion: info.FirmwareVersion,
+		SerialNumber:    info.SerialNumber,
+		Utilization: Utilization{
+			Apu:    parsePercent(info.ApuTotal),
+			Cpu:    parsePercent(info.CpuTotal),
+			Vic:    parsePercent(info.VicTotal),
+			Memory: parsePercent(info.MemoryTotal),
+		},
+		IpeFps:               parseFloat(info.IpeTotal),
+		FanSpeed:             parsePercent(info.FanSpeed),
+		VoltageInput:         parseVolts(info.VoltageInput),
+		PowerDraw:            parseWatts(info.PowerDraw),
+		PowerLimit:           parseWatts(info.PowerLimit),
+		EccErrorsCorrected:   parseCounter(info.DdrEccCorrectedTotal),
+		EccErrorsUncorrected: parseCounter(info.DdrEccUnCorrectedTotal),
+	}
+	if timestamp, ok := parseTimeStamp(info.TimeStamp); ok {
+		m.Timestamp = timestamp
+	}
+	m.BoardIndex, _ = strconv.Atoi(info.BoardIndex)
+	m.ChipCount, _ = strconv.Atoi(info.ChipCount)
+	m.Chips = make([]ChipMetrics, m.ChipCount)
+	for i := 0; i < m.ChipCount; i++ {
+		var pciInfo BoardPciDeviceInfo
+		if i < len(info.PciInfoList) {
+			pciInfo = info.PciInfoList[i]
+		}
+		chip := ChipMetrics{
+			ChipId: getListVal(info.ChipIdList, i),
+			Uuid:   getListVal(info.UuidList, i),
+			Utilization: Utilization{
+				Apu:    parsePercent(getListVal(info.ApuUtilList, i)),
+				Cpu:    parsePercent(getListVal(info.CpuUtilList, i)),
+				Vic:    parsePercent(getListVal(info.VicUtilList, i)),
+				Memory: parsePercent(getListVal(info.MemoryUtilList, i)),
+			},
+			IpeFps:              parseFloat(getListVal(info.IpeUtilList, i)),
+			Temperature:         parseCelsius(getListVal(info.TempUtilList, i)),
+			TemperatureSlowdown: parseCelsius(getListVal(info.TempSlowdownList, i)),
+			TemperatureShutdown: parseCelsius(getListVal(info.TempShutdownList, i)),
+			Voltage:             parseVolts(getListVal(info.ChipVoltageList, i)),
+			Clocks: ChipClocks{
+				Apu:       parseMHz(getListVal(info.ClocksInfo.ApuClocksList, i)),
+				ApuMax:    parseMHz(getListVal(info.ClocksInfo.ApuClocksMaxList, i)),
+				Cpu:       parseMHz(getListVal(info.ClocksInfo.CpuClocksList, i)),
+				CpuMax:    parseMHz(getListVal(info.ClocksInfo.CpuClocksMaxList, i)),
+				Memory:    parseMHz(getListVal(info.ClocksInfo.MemoryClocksList, i)),
+				MemoryMax: parseMHz(getListVal(info.ClocksInfo.MemoryClocksMaxList, i)),
+			},
+			EccMode:              getListVal(info.EccModeList, i),
+			EccErrorsCorrected:   parseCounter(getListVal(info.DdrEccErrCorrectedChipCount, i)),
+			EccErrorsUncorrected: parseCounter(getListVal(info.DdrEccErrUnCorrectedChipCount, i)),
+			Pci: ChipPci{
+				BusId:            pciBusId(pciInfo),
+				VendorId:         pciInfo.VendorId,
+				DeviceId:         pciInfo.DeviceId,
+				SubVendorId:      pciInfo.SubVendorId,
+				SubDeviceId:      pciInfo.SubDeviceId,
+				LinkSpeedCurrent: parseGTps(pciInfo.CurrentSpeed),
+				LinkSpeedMax:     parseGTps(pciInfo.MaxSpeed),
+				LinkWidthCurrent: parseCounter(pciInfo.CurrentWidth),
+				LinkWidthMax:     parseCounter(pciInfo.MaxWidth),
+				NumaNode:         parseInt(pciInfo.NumaNodeId),
+				NumaCPUList:      pciInfo.NumaCPUList,
+			},
+		}
+		chip.ChipIndex = chipIndex(getListVal(info.ChipIndexList, i), m.BoardIndex, m.ChipCount, i)
+		chip.ThrottleReasons = throttleReasons(m, chip)
+		m.Chips[i] = chip
+	}
+	return m
+}
+
+// chipIndex parses the index lynxi-smi reports for the i-th chip of a board, when it is missing or N/A the index is the
+// one of the chip counting the chips of the boards before it.
+func chipIndex(val string, boardIndex int, chipCount int, i int) int {
+	if index, err := strconv.Atoi(val); err == nil {
+		return index
+	}
+	return computeCurrentChipIndex(boardIndex, chipCount) + i
+}
+
+func (c ChipMetrics) EccEnabled() *bool {
+	mode := c.EccMode
+	if mode == "" || strings.Contains(mode, __N_A_STR__) {
+		return nil
+	}
+	enabled := strings.EqualFold(mode, __ENABLED_STR__)
+	return &enabled
+}
+
+// HasUuid returns true when lynxi-smi reports the UUID of the chip.
+func (c ChipMetrics) HasUuid() bool {
+	return c.Uuid != "" && !strings.Contains(c.Uuid, __N_A_STR__)
+}
+
+// TemperatureHeadroom returns the degrees left before the chip slows down, nil when a temperature is not reported.
+func (c ChipMetrics) TemperatureHeadroom() *Celsius {
+	if c.Temperature == nil || c.TemperatureSlowdown == nil {
+		return nil
+	}
+	headroom := *c.TemperatureSlowdown - *c.Temperature
+	return &headroom
+}
+
+// formatTimeStamp formats t as seconds since the epoch with millisecond precision, e.g. 1700000000.123.
+func formatTimeStamp(t time.Time) string {
+	return fmt.Sprintf("%d.%03d", t.Unix(), t.Nanosecond()/int(time.Millisecond))
+}
+
+// parseTimeStamp parses seconds with an optional fraction of any number of digits, digits after nanoseconds are dropped.
+func parseTimeStamp(val string) (time.Time, bool) {
+	parts := strings.SplitN(val, __DOT_SEP__, 2)
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var nsec int64
+	if len(parts) == 2 {
+		frac := parts[1]
+		if len(frac) > __NANO_DIGITS__ {
+			frac = frac[:__NANO_DIGITS__]
+		}
+		if nsec, err = strconv.ParseInt(frac+strings.Repeat("0", __NANO_DIGITS__-len(frac)), 10, 64); err != nil || nsec < 0 {
+			return time.Time{}, false
+		}
+	}
+	return time.Unix(sec, nsec), true
+}
+
+func parseFloatVal(val string) (float64, bool) {
+	fields := strings.Fields(val)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	num := strings.TrimRight(fields[0], "%WCVMHz")
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+func parseFloat(val string) *float64 {
+	v, ok := parseFloatVal(val)
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+func parsePercent(val string) *Percent {
+	v, ok := parseFloatVal(val)
+	if !ok {
+		return nil
+	}
+	p := Percent(v)
+	return &p
+}
+
+func parseCelsius(val string) *Celsius {
+	v, ok := parseFloatVal(val)
+	if !ok {
+		return nil
+	}
+	c := Celsius(v)
+	return &c
+}
+
+func parseWatts(val string) *Watts {
+	v, ok := parseFloatVal(val)
+	if !ok {
+		return nil
+	}
+	w := Watts(v)
+	return &w
+}
+
+func parseVolts(val string) *Volts {
+	v, ok := parseFloatVal(val)
+	if !ok {
+		return nil
+	}
+	volts := Volts(v)
+	return &volts
+}
+
+func parseMHz(val string) *MHz {
+	v, ok := parseFloatVal(val)
+	if !ok {
+		return nil
+	}
+	mhz := MHz(v)
+	return &mhz
+}
+
+func parseGTps(val string) *GTps {
+	v, ok := parseFloatVal(val)
+	if !ok {
+		return nil
+	}
+	gtps := GTps(v)
+	return &gtps
+}
+
+func parseCounter(val string) *uint64 {
+	fields := strings.Fields(val)
+	if len(fields) == 0 {
+		return nil
+	}
+	v, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func parseInt(val string) *int {
+	v, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFloatVal(t *testing.T) {
+	cases := []struct {
+		val  string
+		want float64
+		ok   bool
+	}{
+		{"31.50 W", 31.5, true},
+		{"12.5%", 12.5, true},
+		{"45C", 45, true},
+		{"0.80V", 0.8, true},
+		{"1000MHz", 1000, true},
+		{"8 GT/s", 8, true},
+		{"-2", -2, true},
+		{__N_A_STR__, 0, false},
+		{"", 0, false},
+		{"HP300", 0, false},
+	}
+	for _, c := range cases {
+		if got, ok := parseFloatVal(c.val); got != c.want || ok != c.ok {
+			t.Errorf("parseFloatVal(%q) = %v, %v, want %v, %v", c.val, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestParseCounter(t *testing.T) {
+	if got := parseCounter("12 "); got == nil || *got != 12 {
+		t.Errorf("parseCounter(12) = %v, want 12", got)
+	}
+	for _, val := range []string{__N_A_STR__, "", "-1", "1.5"} {
+		if got := parseCounter(val); got != nil {
+			t.Errorf("parseCounter(%q) = %v, want nil", val, *got)
+		}
+	}
+}
+
+func TestParseTimeStamp(t *testing.T) {
+	cases := []struct {
+		val  string
+		want time.Time
+		ok   bool
+	}{
+		{"1700000000.123", time.Unix(1700000000, 123000000), true},
+		{"1700000000", time.Unix(1700000000, 0), true},
+		{"1700000000.5", time.Unix(1700000000, 500000000), true},
+		{"1700000000.123456", time.Unix(1700000000, 123456000), true},
+		{"1700000000.1234567891", time.Unix(1700000000, 123456789), true},
+		{"1700000000.-5", time.Time{}, false},
+		{"", time.Time{}, false},
+		{"1700000000.abc", time.Time{}, false},
+	}
+	for _, c := range cases {
+		if got, ok := parseTimeStamp(c.val); !got.Equal(c.want) || ok != c.ok {
+			t.Errorf("parseTimeStamp(%q) = %v, %v, want %v, %v", c.val, got, ok, c.want, c.ok)
+		}
+	}
+	if got := formatTimeStamp(time.Unix(1700000000, 123456789)); got != "1700000000.123" {
+		t.Errorf("formatTimeStamp = %s, want 1700000000.123", got)
+	}
+}
+
+func TestNewBoardMetrics(t *testing.T) {
+	info := BoardBaseInfo{
+		BoardIndex:    "1",
+		ChipCount:     "3",
+		SerialNumber:  "2203A0013",
+		TimeStamp:     "1700000000.500",
+		PowerDraw:     "31.50 W",
+		FanSpeed:      __N_A_STR__,
+		ChipIndexList: []string{"3", "4"},
+		UuidList:      []string{"uuid-3", "uuid-4"},
+		TempUtilList:  []string{"45C", __N_A_STR__},
+		PciInfoList:   []BoardPciDeviceInfo{{BusNum: "06", Device: "00", Function: "0", CurrentWidth: "8", MaxSpeed: "8 GT/s"}},
+	}
+	m := NewBoardMetrics(info)
+	if m.BoardIndex != 1 || m.ChipCount != 3 || len(m.Chips) != 3 || !m.Timestamp.Equal(time.Unix(1700000000, 500000000)) {
+		t.Fatalf("NewBoardMetrics = %+v", m)
+	}
+	if m.PowerDraw == nil || *m.PowerDraw != 31.5 || m.FanSpeed != nil {
+		t.Errorf("power draw = %v, fan speed = %v, want 31.5 and nil", m.PowerDraw, m.FanSpeed)
+	}
+	c := m.Chips[0]
+	if c.ChipIndex != 3 || c.Uuid != "uuid-3" || c.Temperature == nil || *c.Temperature != 45 || c.Pci.BusId != "0000:06:00.0" ||
+		c.Pci.LinkWidthCurrent == nil || *c.Pci.LinkWidthCurrent != 8 || c.Pci.LinkSpeedMax == nil || *c.Pci.LinkSpeedMax != 8 {
+		t.Errorf("chip 0 = %+v", c)
+	}
+	if c := m.Chips[1]; c.ChipIndex != 4 || c.Temperature != nil || c.Pci.BusId != "" {
+		t.Errorf("chip 1 = %+v, want chip 4 without temperature and PCI address", c)
+	}
+	if c := m.Chips[2]; c.ChipIndex != 5 || c.Uuid != "" || c.Temperature != nil || c.Pci.LinkWidthCurrent != nil {
+		t.Errorf("chip 2 = %+v, want the index after chip 4 and the other values of a chip missing from the lists to be empty", c)
+	}
+}
@@ -0,0 +1,213 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var update = flag.Bool("update", false, "update the expected.json golden files")
+
+const __GOLDEN_FILE__ = "expected.json"
+
+// withReplaySource runs fn with the captures of testdata/<name> as data source.
+func withReplaySource(t *testing.T, dir string, fn func()) {
+	t.Helper()
+	prev := currentSource
+	SetSource(NewReplaySource(dir))
+	defer SetSource(prev)
+	fn()
+}
+
+func TestQueryBoardsGolden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) == 0 {
+		t.Fatal("no test cases found in testdata")
+	}
+	for _, dir := range dirs {
+		dir := dir
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			var boards []BoardBaseInfo
+			withReplaySource(t, dir, func() {
+				boards, err = QueryBoards()
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range boards {
+				boards[i].TimeStamp = ""
+			}
+			got, err := json.MarshalIndent(boards, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			golden := filepath.Join(dir, __GOLDEN_FILE__)
+			if *update {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v, run go test -update to create it", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("QueryBoards() mismatch for %s\ngot:\n%s\nwant:\n%s", dir, got, want)
+			}
+		})
+	}
+}
+
+func TestListBoardsReplay(t *testing.T) {
+	var boards []BoardSummary
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "multi_board"), func() {
+		boards, err = ListBoards()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BoardSummary{
+		{BoardIndex: 0, ProductName: "HP300", SerialNumber: "2203A0012", ChipCount: 3},
+		{BoardIndex: 1, ProductName: "HP300", SerialNumber: "2203A0013", ChipCount: 3},
+	}
+	if len(boards) != len(want) {
+		t.Fatalf("ListBoards() returned %d boards, want %d", len(boards), len(want))
+	}
+	for i := range want {
+		if boards[i] != want[i] {
+			t.Errorf("ListBoards()[%d] = %+v, want %+v", i, boards[i], want[i])
+		}
+	}
+}
+
+func TestWriteLynSmiDetailInfoReadError(t *testing.T) {
+	readErr := errors.New("read failed")
+	r := bufio.NewReader(io.MultiReader(strings.NewReader("Board: 0\n"), iotest.ErrReader(readErr)))
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "single_chip"), func() {
+		err = writeLynSmiDetailInfo(ioutil.Discard, r, ioutil.NopCloser(nil))
+	})
+	if err != readErr {
+		t.Errorf("writeLynSmiDetailInfo() = %v, want %v", err, readErr)
+	}
+}
+
+func TestRemoveDebugInfoBannerOnly(t *testing.T) {
+	banner, err := ioutil.ReadFile(filepath.Join("testdata", "banner_only", "lynxi-smi_-q"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	readErr := errors.New("read failed")
+	cases := []struct {
+		r    io.Reader
+		want error
+	}{
+		{bytes.NewReader(banner), io.EOF},
+		{io.MultiReader(bytes.NewReader(banner), iotest.ErrReader(readErr)), readErr},
+	}
+	for _, c := range cases {
+		r := bufio.NewReader(c.r)
+		line, err := r.ReadString(__LINE_FEED_SEP__)
+		if err = removeDebugInfo(&line, r, err); err != c.want {
+			t.Errorf("removeDebugInfo() = %v, want %v", err, c.want)
+		}
+	}
+	withReplaySource(t, filepath.Join("testdata", "banner_only"), func() {
+		if boards, err := ListBoards(); err != nil || len(boards) != 0 {
+			t.Errorf("ListBoards() = %v, %v, want no boards", boards, err)
+		}
+	})
+}
+
+func TestQueryVersionReplay(t *testing.T) {
+	cases := []struct {
+		dir         string
+		sdk, driver string
+	}{
+		{"multi_board", VersionShortStr + "1.11.0", VersionShortStr + "1.6.0"},
+		{"single_chip", __UNKNOWN_STR__, VersionShortStr + "1.6.0"},
+	}
+	for _, c := range cases {
+		withReplaySource(t, filepath.Join("testdata", c.dir), func() {
+			if got := queryVersion(SDK); got != c.sdk {
+				t.Errorf("%s: SDK version = %s, want %s", c.dir, got, c.sdk)
+			}
+			if got := queryVersion(Driver); got != c.driver {
+				t.Errorf("%s: driver version = %s, want %s", c.dir, got, c.driver)
+			}
+		})
+	}
+}
+
+// countingSource counts the lynxi-smi calls of the source it wraps.
+type countingSource struct {
+	Source
+	smiCalls int
+}
+
+func (s *countingSource) Smi(arg ...string) (io.ReadCloser, error) {
+	s.smiCalls++
+	return s.Source.Smi(arg...)
+}
+
+func TestQueryFieldsChipCountCached(t *testing.T) {
+	prev := currentSource
+	defer SetSource(prev)
+	s := &countingSource{Source: NewReplaySource(filepath.Join("testdata", "multi_board"))}
+	SetSource(s)
+	for i := 0; i < 3; i++ {
+		if _, err := ParseQueryFields("uuid.chip2"); err != nil {
+			t.Fatal(err)
+		}
+		QueryFieldHelp()
+	}
+	if s.smiCalls != 1 {
+		t.Errorf("lynxi-smi called %d times for the chip count, want 1", s.smiCalls)
+	}
+	SetSource(s)
+	if _, err := ParseQueryFields("uuid.chip3"); err == nil || s.smiCalls != 2 {
+		t.Errorf("ParseQueryFields(uuid.chip3) = %v after %d lynxi-smi calls, want an error after the count is queried again", err, s.smiCalls)
+	}
+}
+
+// TestQueryFieldsFourChips uses the synthetic four_chip_banner case, two boards of four chips written by the simulator.
+func TestQueryFieldsFourChips(t *testing.T) {
+	withReplaySource(t, filepath.Join("testdata", "four_chip_banner"), func() {
+		help := make(map[string]bool)
+		for _, h := range QueryFieldHelp() {
+			help[h.Name] = true
+		}
+		if !help["uuid.chip3"] || !help["temperature.current.chip3"] || help["uuid.chip4"] {
+			t.Errorf("QueryFieldHelp() = %v, want the per chip fields of chips 0 to 3", help)
+		}
+		fields, err := ParseQueryFields("board_index,uuid.chip3,temperature.current.chip3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		boards, err := QueryBoards()
+		if err != nil || len(boards) != 2 {
+			t.Fatalf("QueryBoards() = %d boards, %v", len(boards), err)
+		}
+		want := []string{"1", "1e9f27c5-4c59-4e58-0001-000000000007", __N_A_STR__}
+		if got := QueryFieldValues(boards[1], fields); !reflect.DeepEqual(got, want) {
+			t.Errorf("QueryFieldValues() = %v, want %v", got, want)
+		}
+		if _, err := ParseQueryFields("uuid.chip99"); err == nil {
+			t.Error("ParseQueryFields(uuid.chip99) accepted a chip beyond the chip count")
+		}
+	})
+}
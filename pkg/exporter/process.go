@@ -0,0 +1,245 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultProcRoot         = "/proc"
+	DefaultDevicePathPrefix = "/dev/lynd"
+	// VisibleDevicesEnv lists the chips of a container by index, UUID or PCI address, set by the device plugin and the
+	// CDI spec and read by the OCI runtime wrapper.
+	VisibleDevicesEnv    = "LYNXI_VISIBLE_DEVICES"
+	__PROC_FD_DIR__      = "fd"
+	__PROC_COMM_FILE__   = "comm"
+	__PROC_STATUS_FILE__ = "status"
+	__PROC_CGROUP_FILE__ = "cgroup"
+	__PROC_UID_KEY__     = "Uid:"
+)
+
+// ComputeAppFields are the fields of --query-compute-apps, named after the json tags of ChipProcess.
+var ComputeAppFields = []string{"pid", "process_name", "user", "chip_index", "uuid", "pci.bus_id", "device", "cgroup", "container_id"}
+
+var containerIdRegexp = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// ChipProcess is a process holding the device node of a chip open, ChipIndex is the index of the chip on the system.
+type ChipProcess struct {
+	Pid         int    `json:"pid"`
+	Name        string `json:"process_name"`
+	User        string `json:"user"`
+	ChipIndex   int    `json:"chip_index"`
+	Uuid        string `json:"uuid"`
+	PciBusId    string `json:"pci.bus_id"`
+	DevicePath  string `json:"device"`
+	Cgroup      string `json:"cgroup"`
+	ContainerId string `json:"container_id"`
+}
+
+// DevicePath returns the device node of the chip with the system wide index, e.g. /dev/lynd0.
+func DevicePath(chipIndex int) string {
+	return DefaultDevicePathPrefix + strconv.Itoa(chipIndex)
+}
+
+func ParseComputeAppFields(raw string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(raw, __COMMA_SEP__) {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] {
+			continue
+		}
+		if !isComputeAppField(f) {
+			return nil, fmt.Errorf("field %s is not a valid field to query, expected %s", strconv.Quote(f), strings.Join(ComputeAppFields, __COMMA_SEP__))
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func isComputeAppField(field string) bool {
+	for _, f := range ComputeAppFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func ComputeAppFieldValues(p ChipProcess, fields []string) []string {
+	vals := make([]string, len(fields))
+	for i, f := range fields {
+		switch f {
+		case "pid":
+			vals[i] = strconv.Itoa(p.Pid)
+		case "process_name":
+			vals[i] = p.Name
+		case "user":
+			vals[i] = p.User
+		case "chip_index":
+			vals[i] = strconv.Itoa(p.ChipIndex)
+		case "uuid":
+			vals[i] = p.Uuid
+		case "pci.bus_id":
+			vals[i] = p.PciBusId
+		case "device":
+			vals[i] = p.DevicePath
+		case "cgroup":
+			vals[i] = p.Cgroup
+		case "container_id":
+			vals[i] = p.ContainerId
+		}
+		if vals[i] == "" {
+			vals[i] = __N_A_STR__
+		}
+	}
+	return vals
+}
+
+// parseDevicePath returns the chip index of a device node, e.g. 3 for /dev/lynd3.
+func parseDevicePath(path string) (int, bool) {
+	if !strings.HasPrefix(path, DefaultDevicePathPrefix) {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimPrefix(path, DefaultDevicePathPrefix))
+	if err != nil || index < 0 {
+		return 0, false
+	}
+	return index, true
+}
+
+// QueryChipProcesses scans procRoot/<pid>/fd for open APU device nodes, a process is listed once per chip it holds.
+func QueryChipProcesses(procRoot string) ([]ChipProcess, error) {
+	entries, err := ioutil.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+	var processes []ChipProcess
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		devices := scanProcessDevices(filepath.Join(procRoot, entry.Name()))
+		if len(devices) == 0 {
+			continue
+		}
+		p := readProcessInfo(filepath.Join(procRoot, entry.Name()), pid)
+		for _, device := range devices {
+			p.DevicePath = device
+			p.ChipIndex, _ = parseDevicePath(device)
+			processes = append(processes, p)
+		}
+	}
+	sort.Slice(processes, func(i, j int) bool {
+		if processes[i].ChipIndex != processes[j].ChipIndex {
+			return processes[i].ChipIndex < processes[j].ChipIndex
+		}
+		return processes[i].Pid < processes[j].Pid
+	})
+	if len(processes) > 0 {
+		resolveChipProcesses(processes)
+	}
+	return processes, nil
+}
+
+// scanProcessDevices returns the APU device nodes opened by the process, processes exiting during the scan have none.
+func scanProcessDevices(procDir string) []string {
+	fdDir := filepath.Join(procDir, __PROC_FD_DIR__)
+	fds, err := ioutil.ReadDir(fdDir)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var devices []string
+	for _, fd := range fds {
+		target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+		if err != nil {
+			continue
+		}
+		if _, ok := parseDevicePath(target); ok && !seen[target] {
+			seen[target] = true
+			devices = append(devices, target)
+		}
+	}
+	return devices
+}
+
+func readProcessInfo(procDir string, pid int) ChipProcess {
+	p := ChipProcess{Pid: pid, Name: __N_A_STR__, User: __N_A_STR__}
+	if comm, err := ioutil.ReadFile(filepath.Join(procDir, __PROC_COMM_FILE__)); err == nil {
+		p.Name = strings.TrimSpace(string(comm))
+	}
+	if uid, ok := readProcessUid(procDir); ok {
+		p.User = uid
+		if u, err := user.LookupId(uid); err == nil {
+			p.User = u.Username
+		}
+	}
+	if cgroup, err := ioutil.ReadFile(filepath.Join(procDir, __PROC_CGROUP_FILE__)); err == nil {
+		p.Cgroup, p.ContainerId = parseCgroup(string(cgroup))
+	}
+	return p
+}
+
+func readProcessUid(procDir string) (string, bool) {
+	f, err := os.Open(filepath.Join(procDir, __PROC_STATUS_FILE__))
+	if err != nil {
+		return "", false
+	}
+	defer closeAndLog(f)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[0] == __PROC_UID_KEY__ {
+			return fields[1], true
+		}
+	}
+	return "", false
+}
+
+// parseCgroup returns the cgroup path of the process and the ID of its container, the unified hierarchy is preferred.
+func parseCgroup(content string) (string, string) {
+	var cgroup string
+	for _, line := range strings.Split(content, __LINE_FEED_STR__) {
+		parts := strings.SplitN(line, __COLON_SEP__, 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" || cgroup == "" {
+			cgroup = parts[2]
+		}
+	}
+	return cgroup, containerIdRegexp.FindString(cgroup)
+}
+
+// resolveChipProcesses fills the PCI bus of the chips from the PCI device list and their UUID from lynxi-smi when available.
+func resolveChipProcesses(processes []ChipProcess) {
+	ch := make(chan []string)
+	go QueryLynPciInfo(ch)
+	pciInfoStrList := <-ch
+	uuids := make(map[int]string)
+	if boards, err := QueryBoardMetrics(); err == nil {
+		for _, board := range boards {
+			for _, chip := range board.Chips {
+				uuids[chip.ChipIndex] = chip.Uuid
+			}
+		}
+	}
+	for i := range processes {
+		index := processes[i].ChipIndex
+		if index < len(pciInfoStrList) {
+			processes[i].PciBusId = __PCI_DOMAIN__ + __COLON_SEP__ + pciInfoStrList[index]
+		}
+		processes[i].Uuid = uuids[index]
+	}
+}
@@ -0,0 +1,89 @@
+package exporter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const __TEST_CONTAINER_ID__ = "4f1c0d7e9b2a3c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5"
+
+// fakeProcess writes procRoot/<pid> with fds linked to targets.
+func fakeProcess(t *testing.T, procRoot string, pid string, comm string, uid string, cgroup string, targets ...string) {
+	t.Helper()
+	dir := filepath.Join(procRoot, pid)
+	if err := os.MkdirAll(filepath.Join(dir, __PROC_FD_DIR__), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		__PROC_COMM_FILE__:   comm + "\n",
+		__PROC_STATUS_FILE__: "Name:\t" + comm + "\nUid:\t" + uid + "\t" + uid + "\t" + uid + "\t" + uid + "\n",
+		__PROC_CGROUP_FILE__: cgroup,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, target := range targets {
+		if err := os.Symlink(target, filepath.Join(dir, __PROC_FD_DIR__, string(rune('3'+i)))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestQueryChipProcesses(t *testing.T) {
+	procRoot := t.TempDir()
+	fakeProcess(t, procRoot, "4242", "infer", "4294967290",
+		"12:devices:/docker/"+__TEST_CONTAINER_ID__+"\n0::/system.slice/docker-"+__TEST_CONTAINER_ID__+".scope\n",
+		"/dev/lynd2", "/dev/lynd0", "/dev/lynd2", "/dev/null")
+	fakeProcess(t, procRoot, "17", "bash", "4294967290", "0::/user.slice\n", "/dev/pts/0", "socket:[1234]")
+	fakeProcess(t, procRoot, "99", "train", "4294967290", "0::/user.slice/session-1.scope\n", "/dev/lynd0")
+	if err := os.MkdirAll(filepath.Join(procRoot, "self"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var processes []ChipProcess
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "three_chip"), func() {
+		processes, err = QueryChipProcesses(procRoot)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := ChipProcess{Pid: 4242, Name: "infer", User: "4294967290",
+		Cgroup: "/system.slice/docker-" + __TEST_CONTAINER_ID__ + ".scope", ContainerId: __TEST_CONTAINER_ID__}
+	session := ChipProcess{Pid: 99, Name: "train", User: "4294967290", Cgroup: "/user.slice/session-1.scope"}
+	want := []ChipProcess{session, container, container}
+	for i, index := range []int{0, 0, 2} {
+		want[i].ChipIndex = index
+		want[i].DevicePath = DevicePath(index)
+		want[i].Uuid = "1e9f27c5-4c59-4e58-0000-00000000000" + string(rune('0'+index))
+		want[i].PciBusId = "0000:0" + string(rune('3'+index)) + ":00.0"
+	}
+	if !reflect.DeepEqual(processes, want) {
+		t.Errorf("QueryChipProcesses() =\n%+v\nwant\n%+v", processes, want)
+	}
+}
+
+func TestParseCgroup(t *testing.T) {
+	cases := []struct {
+		content   string
+		cgroup    string
+		container string
+	}{
+		{"0::/user.slice/session-1.scope\n", "/user.slice/session-1.scope", ""},
+		{"12:devices:/docker/" + __TEST_CONTAINER_ID__ + "\n", "/docker/" + __TEST_CONTAINER_ID__, __TEST_CONTAINER_ID__},
+		{"0::/kubepods.slice/kubepods-pod1.slice/cri-containerd-" + __TEST_CONTAINER_ID__ + ".scope\n",
+			"/kubepods.slice/kubepods-pod1.slice/cri-containerd-" + __TEST_CONTAINER_ID__ + ".scope", __TEST_CONTAINER_ID__},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		cgroup, container := parseCgroup(c.content)
+		if cgroup != c.cgroup || container != c.container {
+			t.Errorf("parseCgroup(%q) = %q, %q, want %q, %q", c.content, cgroup, container, c.cgroup, c.container)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ChipIdPrefix selects a chip by its index on the system, e.g. chip4.
+	ChipIdPrefix = "chip"
+)
+
+// DeviceSelection holds the chips selected by --id, a nil selection selects every device.
+type DeviceSelection struct {
+	boards map[int]bool
+	chips  map[int]bool
+}
+
+// ParseDeviceIds splits the comma separated value of --id.
+func ParseDeviceIds(raw string) []string {
+	var ids []string
+	for _, id := range strings.Split(raw, __COMMA_SEP__) {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SelectDevices resolves ids against boards. An id is a board index, a chip index prefixed with chip, a chip UUID,
+// a board serial number or the PCI address of a chip, with or without the domain. Selecting a board selects its chips.
+func SelectDevices(boards []BoardMetrics, ids []string) (*DeviceSelection, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	s := &DeviceSelection{boards: make(map[int]bool), chips: make(map[int]bool)}
+	for _, id := range ids {
+		matched := false
+		for _, b := range boards {
+			if matchBoardId(b, id) {
+				matched = true
+				s.boards[b.BoardIndex] = true
+				for _, c := range b.Chips {
+					s.chips[c.ChipIndex] = true
+				}
+				continue
+			}
+			for _, c := range b.Chips {
+				if matchChipId(c, id) {
+					matched = true
+					s.boards[b.BoardIndex] = true
+					s.chips[c.ChipIndex] = true
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no APU matches the id %s", strconv.Quote(id))
+		}
+	}
+	return s, nil
+}
+
+func matchBoardId(b BoardMetrics, id string) bool {
+	return id == strconv.Itoa(b.BoardIndex) || (b.SerialNumber != "" && id == b.SerialNumber)
+}
+
+func matchChipId(c ChipMetrics, id string) bool {
+	if id == ChipIdPrefix+strconv.Itoa(c.ChipIndex) {
+		return true
+	}
+	if c.Uuid != "" && strings.EqualFold(id, c.Uuid) {
+		return true
+	}
+	busId := strings.ToLower(c.Pci.BusId)
+	if busId == "" {
+		return false
+	}
+	id = strings.ToLower(id)
+	return id == busId || __PCI_DOMAIN__+__COLON_SEP__+id == busId
+}
+
+// HasBoard returns true when a chip of the board is selected.
+func (s *DeviceSelection) HasBoard(boardIndex int) bool {
+	return s == nil || s.boards[boardIndex]
+}
+
+// HasChip returns true when the chip with the index on the system is selected.
+func (s *DeviceSelection) HasChip(chipIndex int) bool {
+	return s == nil || s.chips[chipIndex]
+}
+
+// HasAllChips returns true when every chip of the board is selected.
+func (s *DeviceSelection) HasAllChips(b BoardMetrics) bool {
+	if !s.HasBoard(b.BoardIndex) {
+		return false
+	}
+	for _, c := range b.Chips {
+		if !s.HasChip(c.ChipIndex) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterBoards returns the selected boards with their selected chips.
+func (s *DeviceSelection) FilterBoards(boards []BoardMetrics) []BoardMetrics {
+	if s == nil {
+		return boards
+	}
+	var selected []BoardMetrics
+	for _, b := range boards {
+		if !s.HasBoard(b.BoardIndex) {
+			continue
+		}
+		var chips []ChipMetrics
+		for _, c := range b.Chips {
+			if s.HasChip(c.ChipIndex) {
+				chips = append(chips, c)
+			}
+		}
+		b.Chips = chips
+		selected = append(selected, b)
+	}
+	return selected
+}
+
+// QuerySelection queries the boards and resolves ids against them, it is nil without ids.
+func QuerySelection(ids []string) (*DeviceSelection, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	boards, err := QueryBoardMetrics()
+	if err != nil {
+		return nil, err
+	}
+	return SelectDevices(boards, ids)
+}
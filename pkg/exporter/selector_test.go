@@ -0,0 +1,76 @@
+package exporter
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSelectDevices(t *testing.T) {
+	var boards []BoardMetrics
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "multi_board"), func() {
+		boards, err = QueryBoardMetrics()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		ids    string
+		boards []int
+		chips  []int
+	}{
+		{"", []int{0, 1}, []int{0, 1, 2, 3, 4, 5}},
+		{"1", []int{1}, []int{3, 4, 5}},
+		{"2203A0012", []int{0}, []int{0, 1, 2}},
+		{"chip4", []int{1}, []int{4}},
+		{"1E9F27C5-4C59-4E58-0000-000000000001", []int{0}, []int{1}},
+		{"0000:08:00.0", []int{1}, []int{5}},
+		{" 03:00.0 , chip5", []int{0, 1}, []int{0, 5}},
+	}
+	for _, c := range cases {
+		selection, err := SelectDevices(boards, ParseDeviceIds(c.ids))
+		if err != nil {
+			t.Errorf("SelectDevices(%q): %v", c.ids, err)
+			continue
+		}
+		var gotBoards, gotChips []int
+		for _, b := range selection.FilterBoards(boards) {
+			gotBoards = append(gotBoards, b.BoardIndex)
+			for _, chip := range b.Chips {
+				gotChips = append(gotChips, chip.ChipIndex)
+			}
+		}
+		if !reflect.DeepEqual(gotBoards, c.boards) || !reflect.DeepEqual(gotChips, c.chips) {
+			t.Errorf("SelectDevices(%q) = boards %v chips %v, want boards %v chips %v", c.ids, gotBoards, gotChips, c.boards, c.chips)
+		}
+	}
+	for _, ids := range []string{"2", "chip6", "0000:09:00.0", "2203A9999"} {
+		if _, err := SelectDevices(boards, ParseDeviceIds(ids)); err == nil {
+			t.Errorf("SelectDevices(%q) did not fail", ids)
+		}
+	}
+}
+
+func TestQuerySelectedFieldValues(t *testing.T) {
+	var boards []BoardBaseInfo
+	var err error
+	withReplaySource(t, filepath.Join("testdata", "multi_board"), func() {
+		boards, err = QueryBoards()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	selection, err := SelectDevices(NewBoardMetricsList(boards), []string{"chip4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{"board_index", "temperature.current.chip0", "temperature.current.chip1", "temperature.current.chip2"}
+	want := []string{"1", __N_A_STR__, "46", __N_A_STR__}
+	if got := QuerySelectedFieldValues(boards[1], fields, selection); !reflect.DeepEqual(got, want) {
+		t.Errorf("QuerySelectedFieldValues() = %v, want %v", got, want)
+	}
+	if got := QuerySelectedFieldValues(boards[1], fields, nil); got[1] == __N_A_STR__ {
+		t.Errorf("QuerySelectedFieldValues() without selection = %v", got)
+	}
+}
@@ -0,0 +1,350 @@
+package exporter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source provides the raw lynxi-smi, lspci, dpkg and sysfs data the parser consumes.
+type Source interface {
+	// Smi returns the output of lynxi-smi called with arg.
+	Smi(arg ...string) (io.ReadCloser, error)
+	SmiVersion() (string, error)
+	DriverVersion() (string, error)
+	SdkVersion() (string, error)
+	// PciDevices returns one lspci line per APU chip, starting with the bus address.
+	PciDevices() ([]string, error)
+	// PciDeviceAttr returns the sysfs attribute of the PCI device with the full address, e.g. 0000:03:00.0.
+	PciDeviceAttr(device string, attr string) (string, error)
+	// PciDevicePath returns the PCI hierarchy of the device starting at its root bus, e.g. pci0000:00, 0000:00:01.0, 0000:03:00.0.
+	PciDevicePath(device string) ([]string, error)
+}
+
+const (
+	SourceLynSmi = "lynxi-smi"
+	SourceSysfs  = "sysfs"
+	SourceReplay = "replay"
+)
+
+const (
+	DefaultSysfsRoot    = "/sys"
+	LynVendorID         = "0x1e9f"
+	LynChipDeviceID     = "0x27c5"
+	__PCI_DEVICES_DIR__ = "bus/pci/devices"
+	__PCI_DOMAIN__      = "0000"
+	__REPLAY_SEP__      = "_"
+	__SYSFS_DIR__       = "sys"
+	__PCI_ROOT_BUS__    = "pci"
+)
+
+var ErrNotSupported = errors.New("not supported by the data source")
+
+var currentSource Source = NewLynSmiSource(DefaultSysfsRoot)
+
+func SetSource(s Source) {
+	currentSource = s
+	resetSourceCache()
+}
+
+func CurrentSource() Source {
+	return currentSource
+}
+
+func NewSource(name string, sysfsRoot string, replayDir string) (Source, error) {
+	switch name {
+	case SourceLynSmi:
+		return NewLynSmiSource(sysfsRoot), nil
+	case SourceSysfs:
+		return NewSysfsSource(sysfsRoot), nil
+	case SourceReplay:
+		if replayDir == "" {
+			return nil, errors.New("replay source requires a capture directory")
+		}
+		return NewReplaySource(replayDir), nil
+	}
+	return nil, fmt.Errorf("unknown data source %q", name)
+}
+
+// LynSmiSource runs the lynxi-smi, lspci and dpkg binaries and reads sysfs under SysfsRoot.
+type LynSmiSource struct {
+	SysfsRoot string
+}
+
+func NewLynSmiSource(sysfsRoot string) *LynSmiSource {
+	return &LynSmiSource{SysfsRoot: sysfsRoot}
+}
+
+func (s *LynSmiSource) Smi(arg ...string) (io.ReadCloser, error) {
+	return startCommand(DefaultLynSmiCommand, arg...)
+}
+
+func (s *LynSmiSource) SmiVersion() (string, error) {
+	rc, err := startCommand(DefaultLynSmiCommand, LynSmiVersionCmdParam)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	return parseSmiVersion(bufio.NewReader(rc))
+}
+
+func (s *LynSmiSource) DriverVersion() (string, error) {
+	rc, err := startCommand(DefaultFindLynDriverCommand, append(VersionCmdParam, LynDriverStr)...)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	return parsePackageVersion(bufio.NewReader(rc), LynDriverStr)
+}
+
+func (s *LynSmiSource) SdkVersion() (string, error) {
+	rc, err := startCommand(DefaultFindLynDriverCommand, append(VersionCmdParam, LynSdkStr)...)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	return parsePackageVersion(bufio.NewReader(rc), LynSdkStr)
+}
+
+func (s *LynSmiSource) PciDevices() ([]string, error) {
+	var pciDeviceList []string
+	fn := func(line string) {
+		pciDeviceList = append(pciDeviceList, removeLineBreak(line))
+	}
+	err := RunShellCmdAndArgsAndReadString(fn, DefaultPCICommand, LynChipDeviceIDAndVendorIDCmdParam...)
+	return pciDeviceList, err
+}
+
+func (s *LynSmiSource) PciDeviceAttr(device string, attr string) (string, error) {
+	return readSysfsAttr(s.SysfsRoot, device, attr)
+}
+
+func (s *LynSmiSource) PciDevicePath(device string) ([]string, error) {
+	return readPciDevicePath(s.SysfsRoot, device)
+}
+
+// SysfsSource only reads sysfs under Root, lynxi-smi data is not available.
+type SysfsSource struct {
+	Root string
+}
+
+func NewSysfsSource(root string) *SysfsSource {
+	return &SysfsSource{Root: root}
+}
+
+func (s *SysfsSource) Smi(arg ...string) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *SysfsSource) SmiVersion() (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *SysfsSource) DriverVersion() (string, error) {
+	version, err := ioutil.ReadFile(filepath.Join(s.Root, "module", LynDriverStr, "version"))
+	if err != nil {
+		return "", err
+	}
+	return VersionShortStr + strings.TrimSpace(string(version)), nil
+}
+
+func (s *SysfsSource) SdkVersion() (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *SysfsSource) PciDevices() ([]string, error) {
+	return scanSysfsPciDevices(s.Root)
+}
+
+func (s *SysfsSource) PciDeviceAttr(device string, attr string) (string, error) {
+	return readSysfsAttr(s.Root, device, attr)
+}
+
+func (s *SysfsSource) PciDevicePath(device string) ([]string, error) {
+	return readPciDevicePath(s.Root, device)
+}
+
+// ReplaySource reads outputs captured in Dir. lynxi-smi outputs are stored in files named after
+// the command line joined by "_" (lynxi-smi, lynxi-smi_-q, lynxi-smi_-q_-i_0, lynxi-smi_-v),
+// lspci and dpkg outputs in the lspci and dpkg files, and the sysfs tree under sys/.
+type ReplaySource struct {
+	Dir string
+}
+
+func NewReplaySource(dir string) *ReplaySource {
+	return &ReplaySource{Dir: dir}
+}
+
+func ReplayFileName(command string, arg ...string) string {
+	return strings.Join(append([]string{command}, arg...), __REPLAY_SEP__)
+}
+
+func (s *ReplaySource) Smi(arg ...string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.Dir, ReplayFileName(DefaultLynSmiCommand, arg...)))
+}
+
+func (s *ReplaySource) SmiVersion() (string, error) {
+	f, err := os.Open(filepath.Join(s.Dir, ReplayFileName(DefaultLynSmiCommand, LynSmiVersionCmdParam)))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return parseSmiVersion(bufio.NewReader(f))
+}
+
+func (s *ReplaySource) DriverVersion() (string, error) {
+	f, err := os.Open(filepath.Join(s.Dir, DefaultFindLynDriverCommand))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return parsePackageVersion(bufio.NewReader(f), LynDriverStr)
+}
+
+func (s *ReplaySource) SdkVersion() (string, error) {
+	f, err := os.Open(filepath.Join(s.Dir, DefaultFindLynDriverCommand))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return parsePackageVersion(bufio.NewReader(f), LynSdkStr)
+}
+
+func (s *ReplaySource) PciDevices() ([]string, error) {
+	var pciDeviceList []string
+	f, err := os.Open(filepath.Join(s.Dir, DefaultPCICommand))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			pciDeviceList = append(pciDeviceList, scanner.Text())
+		}
+	}
+	return pciDeviceList, scanner.Err()
+}
+
+// PciDeviceAttr also accepts device directories with ":" replaced by "_", which can be stored in any file system.
+func (s *ReplaySource) PciDeviceAttr(device string, attr string) (string, error) {
+	sysfsRoot := filepath.Join(s.Dir, __SYSFS_DIR__)
+	val, err := readSysfsAttr(sysfsRoot, device, attr)
+	if os.IsNotExist(err) {
+		return readSysfsAttr(sysfsRoot, strings.Replace(device, __COLON_SEP__, __REPLAY_SEP__, -1), attr)
+	}
+	return val, err
+}
+
+func (s *ReplaySource) PciDevicePath(device string) ([]string, error) {
+	return readPciDevicePath(filepath.Join(s.Dir, __SYSFS_DIR__), device)
+}
+
+type commandReadCloser struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+// Close stops reading the output and waits for the command to exit.
+func (c *commandReadCloser) Close() error {
+	_ = c.ReadCloser.Close()
+	return c.cmd.Wait()
+}
+
+func startCommand(command string, arg ...string) (io.ReadCloser, error) {
+	cmd := exec.Command(command, arg...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	return &commandReadCloser{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+func pciDevicesDir(sysfsRoot string) string {
+	return filepath.Join(sysfsRoot, __PCI_DEVICES_DIR__)
+}
+
+func readSysfsAttr(sysfsRoot string, device string, attr string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(pciDevicesDir(sysfsRoot), device, attr))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readPciDevicePath follows the bus/pci/devices link of the device to its directory below devices/pciDDDD:BB.
+func readPciDevicePath(sysfsRoot string, device string) ([]string, error) {
+	path, err := filepath.EvalSymlinks(filepath.Join(pciDevicesDir(sysfsRoot), device))
+	if err != nil {
+		return nil, err
+	}
+	dirs := strings.Split(filepath.ToSlash(path), "/")
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if strings.HasPrefix(dirs[i], __PCI_ROOT_BUS__+__PCI_DOMAIN__) {
+			return dirs[i:], nil
+		}
+	}
+	return nil, fmt.Errorf("PCI root bus of %s not found in %s", device, path)
+}
+
+func scanSysfsPciDevices(sysfsRoot string) ([]string, error) {
+	entries, err := ioutil.ReadDir(pciDevicesDir(sysfsRoot))
+	if err != nil {
+		return nil, err
+	}
+	var pciDeviceList []string
+	for _, e := range entries {
+		vendor, _ := readSysfsAttr(sysfsRoot, e.Name(), VendorID)
+		device, _ := readSysfsAttr(sysfsRoot, e.Name(), DeviceID)
+		if vendor != LynVendorID || device != LynChipDeviceID {
+			continue
+		}
+		address := strings.TrimPrefix(e.Name(), __PCI_DOMAIN__+__COLON_SEP__)
+		pciDeviceList = append(pciDeviceList, fmt.Sprintf("%s Processing accelerators: Device %s:%s",
+			address, strings.TrimPrefix(vendor, "0x"), strings.TrimPrefix(device, "0x")))
+	}
+	sort.Strings(pciDeviceList)
+	return pciDeviceList, nil
+}
+
+func parseSmiVersion(r *bufio.Reader) (string, error) {
+	var smiVersion string
+	fn := func(info string) {
+		infoSlice := strings.Split(info, __COLON_SEP__)
+		if len(infoSlice) > 1 {
+			smiVersion = infoSlice[1]
+		}
+	}
+	scanVersionInfo(r, fn)
+	smiVersion = strings.TrimSpace(removeLineBreak(smiVersion))
+	if smiVersion == "" {
+		return "", errors.New(DefaultLynSmiCommand + " version not found")
+	}
+	return VersionShortStr + smiVersion, nil
+}
+
+// parsePackageVersion returns the version of the package pkg in the output of dpkg -l.
+func parsePackageVersion(r *bufio.Reader, pkg string) (string, error) {
+	var pkgInfo string
+	fn := func(info string) {
+		if strings.Contains(info, pkg) {
+			pkgInfo = info
+		}
+	}
+	scanVersionInfo(r, fn)
+	if pkgInfo == "" {
+		return "", errors.New(pkg + " version not found")
+	}
+	return parserVersionStr(pkgInfo)
+}
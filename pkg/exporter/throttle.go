@@ -0,0 +1,61 @@
+package exporter
+
+const (
+	__ACTIVE_STR__     = "Active"
+	__NOT_ACTIVE_STR__ = "Not Active"
+)
+
+// ThrottleReasons explains why the clocks of a chip are below their maximum, like the clocks_throttle_reasons of
+// nvidia-smi. Active is set when a clock is reduced, the reasons are derived from the temperature, the board power and
+// the utilization. All fields are nil when the clocks are reported as N/A.
+type ThrottleReasons struct {
+	Active   *bool `json:"active"`
+	Thermal  *bool `json:"thermal"`
+	PowerCap *bool `json:"power_cap"`
+	Idle     *bool `json:"idle"`
+	Unknown  *bool `json:"unknown"`
+}
+
+// throttleReasons derives the throttle reasons of chip: thermal when it reached its slowdown temperature, power cap when
+// the board draws its power limit, idle when the APU is not used and unknown for a reduced clock without these reasons.
+func throttleReasons(board BoardMetrics, chip ChipMetrics) ThrottleReasons {
+	clocks := chip.Clocks
+	reported, reduced := false, false
+	for _, c := range [][2]*MHz{{clocks.Apu, clocks.ApuMax}, {clocks.Cpu, clocks.CpuMax}, {clocks.Memory, clocks.MemoryMax}} {
+		if c[0] == nil || c[1] == nil {
+			continue
+		}
+		reported = true
+		reduced = reduced || *c[0] < *c[1]
+	}
+	if !reported {
+		return ThrottleReasons{}
+	}
+	thermal := reduced && chip.Temperature != nil && chip.TemperatureSlowdown != nil && *chip.Temperature >= *chip.TemperatureSlowdown
+	powerCap := reduced && board.PowerDraw != nil && board.PowerLimit != nil && *board.PowerDraw >= *board.PowerLimit
+	idle := reduced && chip.Utilization.Apu != nil && *chip.Utilization.Apu == 0
+	unknown := reduced && !thermal && !powerCap && !idle
+	return ThrottleReasons{Active: &reduced, Thermal: &thermal, PowerCap: &powerCap, Idle: &idle, Unknown: &unknown}
+}
+
+func activeStr(active *bool) string {
+	switch {
+	case active == nil:
+		return __N_A_STR__
+	case *active:
+		return __ACTIVE_STR__
+	}
+	return __NOT_ACTIVE_STR__
+}
+
+// addThrottleReasonFields adds the clocks_throttle_reasons query fields of the chips of board to the flat map.
+func addThrottleReasonFields(mapData map[string]string, board BoardMetrics) {
+	for i, chip := range board.Chips {
+		r := chip.ThrottleReasons
+		mapData[getMapDataKeyIndex(__CLOCKS_THROTTLE_ACTIVE_CHIP_KEY__, i)] = activeStr(r.Active)
+		mapData[getMapDataKeyIndex(__CLOCKS_THROTTLE_THERMAL_CHIP_KEY__, i)] = activeStr(r.Thermal)
+		mapData[getMapDataKeyIndex(__CLOCKS_THROTTLE_POWER_CAP_CHIP_KEY__, i)] = activeStr(r.PowerCap)
+		mapData[getMapDataKeyIndex(__CLOCKS_THROTTLE_IDLE_CHIP_KEY__, i)] = activeStr(r.Idle)
+		mapData[getMapDataKeyIndex(__CLOCKS_THROTTLE_UNKNOWN_CHIP_KEY__, i)] = activeStr(r.Unknown)
+	}
+}
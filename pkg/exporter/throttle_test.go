@@ -0,0 +1,46 @@
+package exporter
+
+import "testing"
+
+func mhz(v MHz) *MHz {
+	return &v
+}
+
+func TestThrottleReasons(t *testing.T) {
+	watts := func(v Watts) *Watts { return &v }
+	percent := func(v Percent) *Percent { return &v }
+	full := ChipClocks{Apu: mhz(1000), ApuMax: mhz(1000), Cpu: mhz(1500), CpuMax: mhz(1500)}
+	reduced := ChipClocks{Apu: mhz(800), ApuMax: mhz(1000), Cpu: mhz(1500), CpuMax: mhz(1500)}
+	slowdown := celsius(95)
+	board := BoardMetrics{PowerDraw: watts(31.5), PowerLimit: watts(75)}
+	capped := BoardMetrics{PowerDraw: watts(75), PowerLimit: watts(75)}
+	cases := []struct {
+		name  string
+		board BoardMetrics
+		chip  ChipMetrics
+		want  []string
+	}{
+		{"full clocks", board, ChipMetrics{Clocks: full, Temperature: celsius(97), TemperatureSlowdown: slowdown},
+			[]string{__NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__}},
+		{"thermal", board, ChipMetrics{Clocks: reduced, Temperature: celsius(97), TemperatureSlowdown: slowdown},
+			[]string{__ACTIVE_STR__, __ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__}},
+		{"power cap", capped, ChipMetrics{Clocks: reduced, Temperature: celsius(45), TemperatureSlowdown: slowdown},
+			[]string{__ACTIVE_STR__, __NOT_ACTIVE_STR__, __ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__}},
+		{"idle", board, ChipMetrics{Clocks: reduced, Utilization: Utilization{Apu: percent(0)}},
+			[]string{__ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__, __ACTIVE_STR__, __NOT_ACTIVE_STR__}},
+		{"unknown", board, ChipMetrics{Clocks: reduced, Utilization: Utilization{Apu: percent(30)}},
+			[]string{__ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__, __NOT_ACTIVE_STR__, __ACTIVE_STR__}},
+		{"clocks not reported", board, ChipMetrics{},
+			[]string{__N_A_STR__, __N_A_STR__, __N_A_STR__, __N_A_STR__, __N_A_STR__}},
+	}
+	for _, c := range cases {
+		r := throttleReasons(c.board, c.chip)
+		got := []string{activeStr(r.Active), activeStr(r.Thermal), activeStr(r.PowerCap), activeStr(r.Idle), activeStr(r.Unknown)}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: throttleReasons = %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Link is the connection between two chips, named like the nvidia-smi topo -m matrix.
+type Link string
+
+const (
+	LinkSelf       Link = "X"
+	LinkBoard      Link = "BRD"
+	LinkSwitch     Link = "PIX"
+	LinkHostBridge Link = "PHB"
+	LinkNumaNode   Link = "NODE"
+	LinkSystem     Link = "SYS"
+	LinkUnknown    Link = __N_A_STR__
+)
+
+// Links lists the connections from the closest to the farthest with their description.
+var Links = []struct {
+	Link        Link
+	Description string
+}{
+	{LinkSelf, "Self"},
+	{LinkBoard, "Connection between chips on the same board"},
+	{LinkSwitch, "Connection traversing PCIe switches, without a PCIe host bridge"},
+	{LinkHostBridge, "Connection traversing a PCIe host bridge"},
+	{LinkNumaNode, "Connection traversing PCIe host bridges within a NUMA node"},
+	{LinkSystem, "Connection traversing the interconnect between NUMA nodes, e.g. across CPU sockets"},
+}
+
+// ChipTopology is the position of a chip on the system, BoardIndex is -1 when lynxi-smi is not available.
+type ChipTopology struct {
+	ChipIndex  int      `json:"chip_index"`
+	BoardIndex int      `json:"board_index"`
+	BusId      string   `json:"pci.bus_id"`
+	PciPath    []string `json:"pci.path"`
+	NumaNode   *int     `json:"numa_node"`
+	CPUList    string   `json:"cpu_affinity"`
+}
+
+// QueryTopology returns the chips in the order of their index on the system, from the PCI device list and sysfs.
+func QueryTopology() ([]ChipTopology, error) {
+	pciDeviceList, err := currentSource.PciDevices()
+	if err != nil {
+		return nil, err
+	}
+	boardIndexes := make(map[int]int)
+	if boards, err := QueryBoardMetrics(); err == nil {
+		for _, b := range boards {
+			for _, c := range b.Chips {
+				boardIndexes[c.ChipIndex] = b.BoardIndex
+			}
+		}
+	}
+	chips := make([]ChipTopology, len(pciDeviceList))
+	for i, line := range pciDeviceList {
+		busId := __PCI_DOMAIN__ + __COLON_SEP__ + strings.Split(line, " ")[0]
+		chip := ChipTopology{ChipIndex: i, BoardIndex: -1, BusId: busId}
+		if boardIndex, ok := boardIndexes[i]; ok {
+			chip.BoardIndex = boardIndex
+		}
+		if path, err := currentSource.PciDevicePath(busId); err == nil {
+			chip.PciPath = path
+		}
+		if numaNode, err := currentSource.PciDeviceAttr(busId, NumaNode); err == nil {
+			if node, err := strconv.Atoi(numaNode); err == nil && node >= 0 {
+				chip.NumaNode = &node
+			}
+		}
+		if cpuList, err := currentSource.PciDeviceAttr(busId, NumaNodeCPUList); err == nil {
+			chip.CPUList = cpuList
+		}
+		chips[i] = chip
+	}
+	return chips, nil
+}
+
+// ChipLink returns the closest connection between the chips a and b.
+func ChipLink(a ChipTopology, b ChipTopology) Link {
+	switch {
+	case a.ChipIndex == b.ChipIndex:
+		return LinkSelf
+	case a.BoardIndex >= 0 && a.BoardIndex == b.BoardIndex:
+		return LinkBoard
+	}
+	if len(a.PciPath) > 0 && len(b.PciPath) > 0 {
+		common := 0
+		for common < len(a.PciPath)-1 && common < len(b.PciPath)-1 && a.PciPath[common] == b.PciPath[common] {
+			common++
+		}
+		switch {
+		case common >= 2:
+			return LinkSwitch
+		case common == 1:
+			return LinkHostBridge
+		}
+	}
+	if a.NumaNode == nil || b.NumaNode == nil {
+		return LinkUnknown
+	}
+	if *a.NumaNode == *b.NumaNode {
+		return LinkNumaNode
+	}
+	return LinkSystem
+}
@@ -0,0 +1,37 @@
+package exporter
+
+import "testing"
+
+func TestChipLink(t *testing.T) {
+	node0, node1 := 0, 1
+	path := func(rootBus string, rootPort string, rest ...string) []string {
+		return append([]string{"pci0000:" + rootBus, "0000:" + rootPort}, rest...)
+	}
+	chips := []ChipTopology{
+		{ChipIndex: 0, BoardIndex: 0, NumaNode: &node0, PciPath: path("00", "00:01.0", "0000:40:00.0", "0000:41:00.0", "0000:03:00.0")},
+		{ChipIndex: 1, BoardIndex: 0, NumaNode: &node0, PciPath: path("00", "00:01.0", "0000:40:00.0", "0000:41:01.0", "0000:04:00.0")},
+		{ChipIndex: 2, BoardIndex: -1, NumaNode: &node0, PciPath: path("00", "00:01.0", "0000:40:00.0", "0000:41:02.0", "0000:05:00.0")},
+		{ChipIndex: 3, BoardIndex: 1, NumaNode: &node0, PciPath: path("00", "00:02.0", "0000:06:00.0")},
+		{ChipIndex: 4, BoardIndex: 2, NumaNode: &node0, PciPath: path("40", "40:01.0", "0000:43:00.0")},
+		{ChipIndex: 5, BoardIndex: 3, NumaNode: &node1, PciPath: path("80", "80:01.0", "0000:83:00.0")},
+		{ChipIndex: 6, BoardIndex: 4},
+	}
+	cases := []struct {
+		a, b int
+		want Link
+	}{
+		{0, 0, LinkSelf},
+		{0, 1, LinkBoard},
+		{0, 2, LinkSwitch},
+		{0, 3, LinkHostBridge},
+		{0, 4, LinkNumaNode},
+		{0, 5, LinkSystem},
+		{5, 4, LinkSystem},
+		{0, 6, LinkUnknown},
+	}
+	for _, c := range cases {
+		if got := ChipLink(chips[c.a], chips[c.b]); got != c.want {
+			t.Errorf("ChipLink(chip%d, chip%d) = %s, want %s", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -9,20 +9,11 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
-	"strings"
 )
 
-func RunShellCmdGetVersionInfo(fn func(string), command string, arg ...string) {
-	r, cmd := runFindVersionInfoCommand(command, arg...)
+func scanVersionInfo(r *bufio.Reader, fn func(string)) {
 	line, err := r.ReadString(__LINE_FEED_SEP__)
-	for err == nil {
-		if strings.Contains(line, "ERROR") || strings.Contains(line, "lynSmi.cpp") || strings.Contains(line, __SN_STR__) || strings.Contains(line, __START_STR__) {
-			line, err = r.ReadString(__LINE_FEED_SEP__)
-		} else {
-			break
-		}
-		line, err = r.ReadString(__LINE_FEED_SEP__)
-	}
+	err = removeDebugInfo(&line, r, err)
 	for {
 		if err != nil || io.EOF == err {
 			break
@@ -31,27 +22,19 @@ func RunShellCmdGetVersionInfo(fn func(string), command string, arg ...string) {
 		line, err = r.ReadString(__LINE_FEED_SEP__)
 	}
 	if err != io.EOF {
-		fmt.Println(err)
-		return
-	}
-	err = cmd.Wait()
-	if err != nil {
-		return
+		log.Debugln(err)
 	}
 }
 
-func RunShellCmdAndArgsAndReadString(fn func(string), command string, arg ...string) {
+func RunShellCmdAndArgsAndReadString(fn func(string), command string, arg ...string) error {
 	cmd := exec.Command(command, arg...)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		_, err := fmt.Fprintln(os.Stderr, "error=>", err.Error())
-		if err != nil {
-			return
-		}
+		return err
 	}
 	err = cmd.Start()
 	if err != nil {
-		return
+		return err
 	}
 	reader := bufio.NewReader(stdout)
 	for {
@@ -61,16 +44,17 @@ func RunShellCmdAndArgsAndReadString(fn func(string), command string, arg ...str
 		}
 		fn(line)
 	}
-	err = cmd.Wait()
-	if err != nil {
-		return
-	}
+	return cmd.Wait()
 }
 
-func RunLynSMICmdAndReadStrings(fn func(string)) {
-	r, cmd := runLynSMICommand()
+func RunLynSMICmdAndReadStrings(fn func(string)) error {
+	r, c, err := runLynSMICommand()
+	if err != nil {
+		return err
+	}
+	defer closeAndLog(c)
 	line, err := r.ReadString(__LINE_FEED_SEP__)
-	removeDebugInfo(&line, r, err)
+	err = removeDebugInfo(&line, r, err)
 	for {
 		if err != nil || io.EOF == err {
 			break
@@ -79,29 +63,9 @@ func RunLynSMICmdAndReadStrings(fn func(string)) {
 		line, err = r.ReadString(__LINE_FEED_SEP__)
 	}
 	if err != io.EOF {
-		fmt.Println(err)
-	}
-	err = cmd.Wait()
-	if err != nil {
-		return
+		return err
 	}
-}
-
-func RunShellCmdAndReadStringByRef(command string, arg ...string) (*bufio.Reader, *exec.Cmd) {
-	cmd := exec.Command(command, arg...)
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		_, err := fmt.Fprintln(os.Stderr, "error=>", err.Error())
-		if err != nil {
-			return nil, nil
-		}
-	}
-	err = cmd.Start()
-	if err != nil {
-		return nil, nil
-	}
-	r := bufio.NewReader(stdout)
-	return r, cmd
+	return nil
 }
 
 func ReadLine(filename string) {
@@ -220,23 +184,30 @@ func structToMap(v interface{}) map[string]interface{} {
 	return mapData
 }
 
-func runLynSMICommandByBoardId(boardId *int) (*bufio.Reader, *exec.Cmd) {
-	return RunShellCmdAndReadStringByRef(DefaultLynSmiCommand, LynSmiDetailInfoCmdParam, LynSmiCardIdCmdParam, strconv.Itoa(*boardId))
+func closeAndLog(c io.Closer) {
+	err := c.Close()
+	if err != nil {
+		log.Debugln(err)
+	}
 }
 
-func runLynSMICommandByChipIDAndBoardId(boardId *int, chipId *int) (*bufio.Reader, *exec.Cmd) {
-	return RunShellCmdAndReadStringByRef(DefaultLynSmiCommand, LynSmiDetailInfoCmdParam, LynSmiCardIdCmdParam, strconv.Itoa(*boardId),
-		LynSmiChipIdCmdParam, strconv.Itoa(*chipId))
+func runLynSMICommandByBoardId(boardId *int) (*bufio.Reader, io.Closer, error) {
+	return runLynSMICommand(LynSmiDetailInfoCmdParam, LynSmiCardIdCmdParam, strconv.Itoa(*boardId))
 }
 
-func runLynSMICommand() (*bufio.Reader, *exec.Cmd) {
-	return RunShellCmdAndReadStringByRef(DefaultLynSmiCommand)
+func runLynSMICommandByChipIDAndBoardId(boardId *int, chipId *int) (*bufio.Reader, io.Closer, error) {
+	return runLynSMICommand(LynSmiDetailInfoCmdParam, LynSmiCardIdCmdParam, strconv.Itoa(*boardId),
+		LynSmiChipIdCmdParam, strconv.Itoa(*chipId))
 }
 
-func runLynSMIDetailCommand() (*bufio.Reader, *exec.Cmd) {
-	return RunShellCmdAndReadStringByRef(DefaultLynSmiCommand, LynSmiDetailInfoCmdParam)
+func runLynSMICommand(arg ...string) (*bufio.Reader, io.Closer, error) {
+	rc, err := currentSource.Smi(arg...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("run %s failed: %v", DefaultLynSmiCommand, err)
+	}
+	return bufio.NewReader(rc), rc, nil
 }
 
-func runFindVersionInfoCommand(cmd string, arg ...string) (*bufio.Reader, *exec.Cmd) {
-	return RunShellCmdAndReadStringByRef(cmd, arg...)
+func runLynSMIDetailCommand() (*bufio.Reader, io.Closer, error) {
+	return runLynSMICommand(LynSmiDetailInfoCmdParam)
 }
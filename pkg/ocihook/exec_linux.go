@@ -0,0 +1,19 @@
+//go:build linux
+// +build linux
+
+package ocihook
+
+import (
+	"golang.org/x/sys/unix"
+	"os"
+	"os/exec"
+)
+
+// execRuntime replaces the process with the runtime, which keeps the file descriptors and the pid its caller expects.
+func execRuntime(runtime string, args []string) error {
+	path, err := exec.LookPath(runtime)
+	if err != nil {
+		return err
+	}
+	return unix.Exec(path, append([]string{runtime}, args...), os.Environ())
+}
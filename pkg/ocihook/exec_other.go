@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package ocihook
+
+import "errors"
+
+func execRuntime(runtime string, args []string) error {
+	return errors.New("running the runtime is only supported on Linux")
+}
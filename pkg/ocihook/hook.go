@@ -0,0 +1,274 @@
+// Package ocihook adds the chips listed in LYNXI_VISIBLE_DEVICES to a container, as device nodes and cgroup device
+// rules. As an OCI prestart hook it creates them in the created container, the runtime has already read config.json.
+// Before the container is created, a wrapper of the runtime adds them to the config.json of the bundle instead.
+package ocihook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"lynxi_smi_pro/pkg/exporter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ConfigFile        = "config.json"
+	__ALL_DEVICES__   = "all"
+	__DEVICE_ACCESS__ = "rwm"
+	__CHAR_DEVICE__   = "c"
+)
+
+// noDevices are the values of LYNXI_VISIBLE_DEVICES without device, void is set by the CDI spec of the chips.
+var noDevices = []string{"", "void", "none"}
+
+// LinuxDevice is a device node of the runtime spec.
+type LinuxDevice struct {
+	Path     string  `json:"path"`
+	Type     string  `json:"type"`
+	Major    int64   `json:"major"`
+	Minor    int64   `json:"minor"`
+	FileMode *uint32 `json:"fileMode,omitempty"`
+	Uid      *uint32 `json:"uid,omitempty"`
+	Gid      *uint32 `json:"gid,omitempty"`
+}
+
+// LinuxDeviceCgroup is a device rule of the cgroup of the runtime spec.
+type LinuxDeviceCgroup struct {
+	Allow  bool   `json:"allow"`
+	Type   string `json:"type,omitempty"`
+	Major  *int64 `json:"major,omitempty"`
+	Minor  *int64 `json:"minor,omitempty"`
+	Access string `json:"access,omitempty"`
+}
+
+// Options configures Inject, Prestart and RunRuntime. Chips enumerates the chips, Stat reads a device node of the host,
+// Mknod creates a device node, Exec runs the runtime with its arguments, the exporter, the file system of the host and an
+// exec of the runtime when nil. ProcRoot and CgroupRoot are the proc and cgroup trees of the host.
+type Options struct {
+	Chips      func() ([]exporter.ChipDevice, error)
+	Stat       func(path string) (LinuxDevice, error)
+	Mknod      func(path string, d LinuxDevice) error
+	Exec       func(runtime string, args []string) error
+	ProcRoot   string
+	CgroupRoot string
+}
+
+// ParseVisibleDevices splits LYNXI_VISIBLE_DEVICES into chip indexes, UUIDs and PCI addresses, all selects every chip.
+func ParseVisibleDevices(val string) (ids []string, all bool) {
+	val = strings.TrimSpace(val)
+	for _, none := range noDevices {
+		if val == none {
+			return nil, false
+		}
+	}
+	for _, id := range strings.Split(val, ",") {
+		id = strings.TrimSpace(id)
+		switch {
+		case id == __ALL_DEVICES__:
+			return nil, true
+		case id != "":
+			ids = append(ids, id)
+		}
+	}
+	return ids, false
+}
+
+// ResolveVisibleDevices returns the chips selected by ids in their order, without duplicates.
+func ResolveVisibleDevices(chips []exporter.ChipDevice, ids []string, all bool) ([]exporter.ChipDevice, error) {
+	if all {
+		return chips, nil
+	}
+	var selected []exporter.ChipDevice
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		found := false
+		for _, c := range chips {
+			if strconv.Itoa(c.Index) == id || strings.EqualFold(c.Uuid, id) || strings.EqualFold(c.BusId, id) {
+				found = true
+				if !seen[c.Index] {
+					seen[c.Index] = true
+					selected = append(selected, c)
+				}
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown device %s in %s", strconv.Quote(id), exporter.VisibleDevicesEnv)
+		}
+	}
+	return selected, nil
+}
+
+// Inject adds the chips of LYNXI_VISIBLE_DEVICES to the config.json of the bundle, devices and rules already in the
+// config are kept. The config is not written when the container does not ask for chips.
+func Inject(bundle string, opts Options) error {
+	path := filepath.Join(bundle, ConfigFile)
+	config, err := readBundleConfig(path)
+	if err != nil {
+		return err
+	}
+	devices, err := visibleDevices(config, path, opts)
+	if err != nil || len(devices) == 0 {
+		return err
+	}
+	if err := addDevices(config, devices); err != nil {
+		return fmt.Errorf("cannot add the devices to %s: %v", path, err)
+	}
+	data, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, info.Mode()); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func readBundleConfig(path string) (map[string]json.RawMessage, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// visibleDevices returns the device nodes of the host for the chips in LYNXI_VISIBLE_DEVICES of the process of config,
+// none when the container does not ask for chips.
+func visibleDevices(config map[string]json.RawMessage, path string, opts Options) ([]LinuxDevice, error) {
+	if opts.Chips == nil {
+		opts.Chips = exporter.QueryChipDevices
+	}
+	if opts.Stat == nil {
+		opts.Stat = statDevice
+	}
+	var process struct {
+		Env []string `json:"env"`
+	}
+	if raw, ok := config["process"]; ok {
+		if err := json.Unmarshal(raw, &process); err != nil {
+			return nil, fmt.Errorf("cannot decode the process of %s: %v", path, err)
+		}
+	}
+	ids, all := ParseVisibleDevices(getEnv(process.Env, exporter.VisibleDevicesEnv))
+	if len(ids) == 0 && !all {
+		return nil, nil
+	}
+	chips, err := opts.Chips()
+	if err != nil {
+		return nil, err
+	}
+	selected, err := ResolveVisibleDevices(chips, ids, all)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]LinuxDevice, len(selected))
+	for i, c := range selected {
+		if devices[i], err = opts.Stat(exporter.DevicePath(c.Index)); err != nil {
+			return nil, err
+		}
+	}
+	return devices, nil
+}
+
+// getEnv returns the value of the last definition of name in env, like the environment of the container.
+func getEnv(env []string, name string) string {
+	val := ""
+	for _, e := range env {
+		if strings.HasPrefix(e, name+"=") {
+			val = strings.TrimPrefix(e, name+"=")
+		}
+	}
+	return val
+}
+
+// addDevices adds the device nodes to linux.devices and their rules to linux.resources.devices of config, the other
+// fields are kept as they are.
+func addDevices(config map[string]json.RawMessage, devices []LinuxDevice) error {
+	linux := make(map[string]json.RawMessage)
+	if err := unmarshalField(config, "linux", &linux); err != nil {
+		return err
+	}
+	var nodes []LinuxDevice
+	if err := unmarshalField(linux, "devices", &nodes); err != nil {
+		return err
+	}
+	resources := make(map[string]json.RawMessage)
+	if err := unmarshalField(linux, "resources", &resources); err != nil {
+		return err
+	}
+	var rules []LinuxDeviceCgroup
+	if err := unmarshalField(resources, "devices", &rules); err != nil {
+		return err
+	}
+	for _, d := range devices {
+		if !hasDevice(nodes, d.Path) {
+			nodes = append(nodes, d)
+		}
+		if !hasRule(rules, d) {
+			major, minor := d.Major, d.Minor
+			rules = append(rules, LinuxDeviceCgroup{Allow: true, Type: d.Type, Major: &major, Minor: &minor, Access: __DEVICE_ACCESS__})
+		}
+	}
+	if err := marshalField(resources, "devices", rules); err != nil {
+		return err
+	}
+	if err := marshalField(linux, "resources", resources); err != nil {
+		return err
+	}
+	if err := marshalField(linux, "devices", nodes); err != nil {
+		return err
+	}
+	return marshalField(config, "linux", linux)
+}
+
+func unmarshalField(m map[string]json.RawMessage, key string, v interface{}) error {
+	raw, ok := m[key]
+	if !ok || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return nil
+}
+
+func marshalField(m map[string]json.RawMessage, key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	m[key] = raw
+	return nil
+}
+
+func hasDevice(nodes []LinuxDevice, path string) bool {
+	for _, n := range nodes {
+		if n.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRule reports whether a rule allows the device explicitly, later rules override the earlier ones like a deny all.
+func hasRule(rules []LinuxDeviceCgroup, d LinuxDevice) bool {
+	for _, r := range rules {
+		if r.Allow && r.Type == d.Type && r.Major != nil && *r.Major == d.Major && r.Minor != nil && *r.Minor == d.Minor &&
+			strings.Contains(r.Access, "r") && strings.Contains(r.Access, "w") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package ocihook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"lynxi_smi_pro/pkg/exporter"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testChips = []exporter.ChipDevice{
+	{Index: 0, Uuid: "1e9f27c5-4c59-4e58-0000-000000000000", BusId: "0000:03:00.0"},
+	{Index: 1, Uuid: "1e9f27c5-4c59-4e58-0000-000000000001", BusId: "0000:04:00.0"},
+	{Index: 2, Uuid: "1e9f27c5-4c59-4e58-0000-000000000002", BusId: "0000:05:00.0"},
+}
+
+var testOptions = Options{
+	Chips: func() ([]exporter.ChipDevice, error) { return testChips, nil },
+	Stat: func(path string) (LinuxDevice, error) {
+		var minor int64
+		if _, err := fmt.Sscanf(path, exporter.DefaultDevicePathPrefix+"%d", &minor); err != nil {
+			return LinuxDevice{}, err
+		}
+		mode := uint32(0666)
+		return LinuxDevice{Path: path, Type: __CHAR_DEVICE__, Major: 240, Minor: minor, FileMode: &mode}, nil
+	},
+}
+
+// copyBundle copies the sample bundle to a temporary directory, with LYNXI_VISIBLE_DEVICES set to visible.
+func copyBundle(t *testing.T, visible string) string {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "bundle", ConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), "LYNXI_VISIBLE_DEVICES=1,1e9f27c5-4c59-4e58-0000-000000000002",
+		exporter.VisibleDevicesEnv+"="+visible, 1))
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, ConfigFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+type testConfig struct {
+	Hostname string `json:"hostname"`
+	Linux    struct {
+		Devices   []LinuxDevice `json:"devices"`
+		Resources struct {
+			Devices []LinuxDeviceCgroup `json:"devices"`
+		} `json:"resources"`
+		Namespaces []json.RawMessage `json:"namespaces"`
+	} `json:"linux"`
+}
+
+func readConfig(t *testing.T, bundle string) (testConfig, string) {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join(bundle, ConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config testConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	return config, string(data)
+}
+
+func TestInject(t *testing.T) {
+	cases := []struct {
+		visible string
+		paths   []string
+	}{
+		{"1,1e9f27c5-4c59-4e58-0000-000000000002", []string{"/dev/lynd1", "/dev/lynd2"}},
+		{"all", []string{"/dev/lynd0", "/dev/lynd1", "/dev/lynd2"}},
+		{"0000:03:00.0,0", []string{"/dev/lynd0"}},
+		{"void", nil},
+	}
+	for _, c := range cases {
+		bundle := copyBundle(t, c.visible)
+		if err := Inject(bundle, testOptions); err != nil {
+			t.Errorf("Inject(%s) = %v", c.visible, err)
+			continue
+		}
+		config, raw := readConfig(t, bundle)
+		var paths []string
+		for _, d := range config.Linux.Devices {
+			paths = append(paths, d.Path)
+		}
+		if !reflect.DeepEqual(paths, c.paths) {
+			t.Errorf("Inject(%s) devices = %v, want %v", c.visible, paths, c.paths)
+		}
+		rules := config.Linux.Resources.Devices
+		if len(rules) != len(c.paths)+1 || rules[0].Allow {
+			t.Errorf("Inject(%s) cgroup rules = %s", c.visible, raw)
+		}
+		for i, r := range rules[1:] {
+			d := config.Linux.Devices[i]
+			if !r.Allow || r.Type != "c" || *r.Major != 240 || *r.Minor != d.Minor || r.Access != "rwm" {
+				t.Errorf("Inject(%s) cgroup rule of %s = %+v", c.visible, d.Path, r)
+			}
+		}
+		if config.Hostname != "infer" || len(config.Linux.Namespaces) != 2 {
+			t.Errorf("Inject(%s) did not keep the other fields:\n%s", c.visible, raw)
+		}
+	}
+}
+
+func TestInjectTwice(t *testing.T) {
+	bundle := copyBundle(t, "2")
+	for i := 0; i < 2; i++ {
+		if err := Inject(bundle, testOptions); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config, raw := readConfig(t, bundle)
+	if len(config.Linux.Devices) != 1 || len(config.Linux.Resources.Devices) != 2 {
+		t.Errorf("devices added twice:\n%s", raw)
+	}
+}
+
+func TestInjectUnknownDevice(t *testing.T) {
+	bundle := copyBundle(t, "0,chip7")
+	if err := Inject(bundle, testOptions); err == nil || !strings.Contains(err.Error(), `"chip7"`) {
+		t.Errorf("Inject = %v, want an unknown device error", err)
+	}
+}
+
+func TestCreateBundle(t *testing.T) {
+	cases := []struct {
+		args   []string
+		bundle string
+		ok     bool
+	}{
+		{[]string{"--root", "/run/runc", "--log=/run/runc.log", "create", "--bundle", "/run/bundle", "infer"}, "/run/bundle", true},
+		{[]string{"--systemd-cgroup", "run", "-b=/run/bundle", "--pid-file", "/run/infer.pid", "infer"}, "/run/bundle", true},
+		{[]string{"create", "infer"}, ".", true},
+		{[]string{"--root", "create", "start", "infer"}, "", false},
+		{[]string{"delete", "--force", "infer"}, "", false},
+		{[]string{"--version"}, "", false},
+	}
+	for _, c := range cases {
+		if bundle, ok := CreateBundle(c.args); bundle != c.bundle || ok != c.ok {
+			t.Errorf("CreateBundle(%v) = %s, %v, want %s, %v", c.args, bundle, ok, c.bundle, c.ok)
+		}
+	}
+}
+
+func TestRunRuntime(t *testing.T) {
+	bundle := copyBundle(t, "1")
+	var devices []LinuxDevice
+	var runArgs []string
+	opts := testOptions
+	opts.Exec = func(runtime string, args []string) error {
+		config, _ := readConfig(t, bundle)
+		devices, runArgs = config.Linux.Devices, append([]string{runtime}, args...)
+		return nil
+	}
+	args := []string{"--root", "/run/runc", "create", "--bundle", bundle, "infer"}
+	if err := RunRuntime(DefaultRuntime, args, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || devices[0].Path != "/dev/lynd1" {
+		t.Errorf("devices of the bundle when the runtime runs = %v", devices)
+	}
+	if want := append([]string{DefaultRuntime}, args...); !reflect.DeepEqual(runArgs, want) {
+		t.Errorf("runtime run with %v, want %v", runArgs, want)
+	}
+
+	runArgs = nil
+	bundle = copyBundle(t, "chip7")
+	if err := RunRuntime(DefaultRuntime, []string{"create", "--bundle", bundle, "infer"}, opts); err == nil || runArgs != nil {
+		t.Errorf("RunRuntime with an unknown device = %v, runtime run with %v", err, runArgs)
+	}
+	if err := RunRuntime(DefaultRuntime, []string{"start", "infer"}, opts); err != nil || runArgs == nil {
+		t.Errorf("RunRuntime(start) = %v, runtime run with %v", err, runArgs)
+	}
+}
+
+func TestReadState(t *testing.T) {
+	state, err := ReadState(strings.NewReader(`{"ociVersion":"1.0.2","id":"infer","status":"created","pid":42,"bundle":"/run/bundle"}`))
+	if err != nil || state.Bundle != "/run/bundle" || state.Pid != 42 || state.Id != "infer" {
+		t.Errorf("ReadState = %+v, %v", state, err)
+	}
+	for _, raw := range []string{`{"id":"infer","pid":42}`, `{"id":"infer","bundle":"/run/bundle"}`} {
+		if _, err := ReadState(strings.NewReader(raw)); err == nil {
+			t.Errorf("ReadState(%s) did not fail", raw)
+		}
+	}
+}
+
+func TestPrestart(t *testing.T) {
+	procRoot, cgroupRoot := t.TempDir(), t.TempDir()
+	procDir := filepath.Join(procRoot, "42")
+	allow := filepath.Join(cgroupRoot, "devices", "docker", "infer", "devices.allow")
+	for _, dir := range []string{filepath.Join(procDir, "root"), filepath.Dir(allow)} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cgroup := "12:memory:/docker/infer\n5:devices:/docker/infer\n0::/docker/infer\n"
+	if err := ioutil.WriteFile(filepath.Join(procDir, "cgroup"), []byte(cgroup), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(allow, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	var nodes []string
+	opts := testOptions
+	opts.ProcRoot, opts.CgroupRoot = procRoot, cgroupRoot
+	opts.Mknod = func(path string, d LinuxDevice) error {
+		nodes = append(nodes, path)
+		return nil
+	}
+	bundle := copyBundle(t, "1,1e9f27c5-4c59-4e58-0000-000000000002")
+	if err := Prestart(State{Id: "infer", Pid: 42, Bundle: bundle}, opts); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(procDir, "root", "dev", "lynd1"), filepath.Join(procDir, "root", "dev", "lynd2")}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("Prestart created %v, want %v", nodes, want)
+	}
+	if data, _ := ioutil.ReadFile(allow); string(data) != "c 240:1 rwm\nc 240:2 rwm\n" {
+		t.Errorf("devices.allow = %q", data)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(procDir, "cgroup"), []byte("0::/docker/infer\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Prestart(State{Id: "infer", Pid: 42, Bundle: bundle}, opts); err == nil || !strings.Contains(err.Error(), "no devices cgroup") {
+		t.Errorf("Prestart on cgroup v2 = %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package ocihook
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultProcRoot      = "/proc"
+	DefaultCgroupRoot    = "/sys/fs/cgroup"
+	__PROC_ROOT_DIR__    = "root"
+	__PROC_CGROUP_FILE__ = "cgroup"
+	__DEVICES_CGROUP__   = "devices"
+	__DEVICES_ALLOW__    = "devices.allow"
+)
+
+// State is the container state the runtime passes to the hook on stdin, with the fields used by the hook.
+type State struct {
+	Id     string `json:"id"`
+	Pid    int    `json:"pid"`
+	Bundle string `json:"bundle"`
+}
+
+// ReadState decodes the container state the runtime writes to the stdin of the hook.
+func ReadState(r io.Reader) (State, error) {
+	var state State
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return state, fmt.Errorf("cannot decode the container state: %v", err)
+	}
+	if state.Bundle == "" {
+		return state, fmt.Errorf("no bundle in the container state")
+	}
+	if state.Pid <= 0 {
+		return state, fmt.Errorf("no pid in the container state")
+	}
+	return state, nil
+}
+
+// Prestart adds the chips of LYNXI_VISIBLE_DEVICES in the config.json of the bundle to the created container of state:
+// the device nodes are created in /dev below the root of the container process and allowed in its devices cgroup.
+// Only the devices controller of cgroup v1 takes rules from a hook, cgroup v2 needs the devices in config.json.
+func Prestart(state State, opts Options) error {
+	if opts.ProcRoot == "" {
+		opts.ProcRoot = DefaultProcRoot
+	}
+	if opts.CgroupRoot == "" {
+		opts.CgroupRoot = DefaultCgroupRoot
+	}
+	if opts.Mknod == nil {
+		opts.Mknod = makeDevice
+	}
+	path := filepath.Join(state.Bundle, ConfigFile)
+	config, err := readBundleConfig(path)
+	if err != nil {
+		return err
+	}
+	devices, err := visibleDevices(config, path, opts)
+	if err != nil || len(devices) == 0 {
+		return err
+	}
+	procDir := filepath.Join(opts.ProcRoot, strconv.Itoa(state.Pid))
+	allow, err := devicesCgroupFile(procDir, opts.CgroupRoot)
+	if err != nil {
+		return err
+	}
+	root := filepath.Join(procDir, __PROC_ROOT_DIR__)
+	for _, d := range devices {
+		if err := opts.Mknod(filepath.Join(root, d.Path), d); err != nil {
+			return fmt.Errorf("cannot create %s in the container: %v", d.Path, err)
+		}
+		if err := allowDevice(allow, d); err != nil {
+			return fmt.Errorf("cannot allow %s in the devices cgroup: %v", d.Path, err)
+		}
+	}
+	return nil
+}
+
+// allowDevice writes the rule of d to devices.allow, the kernel takes one rule per write.
+func allowDevice(allow string, d LinuxDevice) error {
+	f, err := os.OpenFile(allow, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "%s %d:%d %s\n", d.Type, d.Major, d.Minor, __DEVICE_ACCESS__); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// devicesCgroupFile returns the devices.allow file of the devices cgroup of the process, from its cgroup file.
+func devicesCgroupFile(procDir string, cgroupRoot string) (string, error) {
+	f, err := os.Open(filepath.Join(procDir, __PROC_CGROUP_FILE__))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			if controller == __DEVICES_CGROUP__ {
+				return filepath.Join(cgroupRoot, __DEVICES_CGROUP__, fields[2], __DEVICES_ALLOW__), nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("the container has no devices cgroup, add the chips to config.json before it is created with the runtime wrapper")
+}
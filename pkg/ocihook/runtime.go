@@ -0,0 +1,84 @@
+package ocihook
+
+import "strings"
+
+const (
+	DefaultRuntime     = "runc"
+	__BUNDLE_FLAG__    = "bundle"
+	__BUNDLE_SHORT__   = "b"
+	__CURRENT_BUNDLE__ = "."
+)
+
+// runtimeValueFlags are the global flags of runc taking a value, createCommands the commands creating a container.
+var (
+	runtimeValueFlags = []string{"root", "log", "log-format", "criu", "rootless"}
+	createCommands    = []string{"create", "run"}
+)
+
+// CreateBundle returns the bundle of the container created by the runtime arguments args, the current directory without
+// --bundle. It is false for the other commands of the runtime.
+func CreateBundle(args []string) (string, bool) {
+	command := -1
+	for i := 0; i < len(args); i++ {
+		name, _, hasValue := splitFlag(args[i])
+		if name == "" {
+			command = i
+			break
+		}
+		if !hasValue && contains(runtimeValueFlags, name) {
+			i++
+		}
+	}
+	if command < 0 || !contains(createCommands, args[command]) {
+		return "", false
+	}
+	bundle := __CURRENT_BUNDLE__
+	for i := command + 1; i < len(args); i++ {
+		name, val, hasValue := splitFlag(args[i])
+		if name != __BUNDLE_FLAG__ && name != __BUNDLE_SHORT__ {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			val = args[i]
+		}
+		bundle = val
+	}
+	return bundle, true
+}
+
+// RunRuntime adds the chips of LYNXI_VISIBLE_DEVICES to the bundle when args create a container, then runs the runtime
+// with args. The runtime is not run when the chips cannot be added.
+func RunRuntime(runtime string, args []string, opts Options) error {
+	if bundle, ok := CreateBundle(args); ok {
+		if err := Inject(bundle, opts); err != nil {
+			return err
+		}
+	}
+	if opts.Exec == nil {
+		opts.Exec = execRuntime
+	}
+	return opts.Exec(runtime, args)
+}
+
+// splitFlag returns the name of the flag arg, with one or two dashes, and its value when it is given with =. The name
+// is empty for the arguments that are not flags.
+func splitFlag(arg string) (name string, val string, hasValue bool) {
+	if !strings.HasPrefix(arg, "-") || arg == "-" {
+		return "", "", false
+	}
+	name = strings.TrimLeft(arg, "-")
+	if i := strings.Index(name, "="); i >= 0 {
+		return name[:i], name[i+1:], true
+	}
+	return name, "", false
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+//go:build linux
+// +build linux
+
+package ocihook
+
+import (
+	"fmt"
+	"golang.org/x/sys/unix"
+	"os"
+	"path/filepath"
+)
+
+// statDevice reads the type, numbers, mode and owner of a device node of the host.
+func statDevice(path string) (LinuxDevice, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return LinuxDevice{}, fmt.Errorf("cannot stat %s: %v", path, err)
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFCHR {
+		return LinuxDevice{}, fmt.Errorf("%s is not a character device", path)
+	}
+	mode := st.Mode &^ unix.S_IFMT
+	uid, gid := st.Uid, st.Gid
+	return LinuxDevice{
+		Path:     path,
+		Type:     __CHAR_DEVICE__,
+		Major:    int64(unix.Major(uint64(st.Rdev))),
+		Minor:    int64(unix.Minor(uint64(st.Rdev))),
+		FileMode: &mode,
+		Uid:      &uid,
+		Gid:      &gid,
+	}, nil
+}
+
+// makeDevice creates the character device node d at path with its mode, without umask, and owner. An existing node is
+// kept.
+func makeDevice(path string, d LinuxDevice) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	mode := uint32(0666)
+	if d.FileMode != nil {
+		mode = *d.FileMode
+	}
+	err := unix.Mknod(path, unix.S_IFCHR|mode, int(unix.Mkdev(uint32(d.Major), uint32(d.Minor))))
+	if err == unix.EEXIST {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := unix.Chmod(path, mode); err != nil {
+		return err
+	}
+	if d.Uid != nil && d.Gid != nil {
+		return os.Chown(path, int(*d.Uid), int(*d.Gid))
+	}
+	return nil
+}
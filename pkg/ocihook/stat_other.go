@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package ocihook
+
+import "errors"
+
+func statDevice(path string) (LinuxDevice, error) {
+	return LinuxDevice{}, errors.New("device nodes are only supported on Linux")
+}
+
+func makeDevice(path string, d LinuxDevice) error {
+	return errors.New("device nodes are only supported on Linux")
+}